@@ -0,0 +1,279 @@
+// Package analyser computes metrics over the parsed files of a Go package.
+//
+// Every metric implements Metric and is registered with Register. Code
+// importing the package can register its own metrics before calling Analyse
+// and their results show up in Result.Metrics alongside the built-in ones.
+// Metrics can also implement Describer to explain what they measure, and
+// Versioned and FileMetric so their results can be cached with
+// AnalyseCached.
+package analyser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"time"
+)
+
+// Metric computes a single measurement over the files of a package. A new
+// Metric is created for every package analysed, each file of the package is
+// passed to Process and Result is called once all files have been processed.
+type Metric interface {
+	Name() string
+	Process(fset *token.FileSet, file *ast.File)
+	Result() interface{}
+}
+
+// TypedMetric is a Metric that can be more precise given the type
+// information of a package. When a package is analysed with AnalyseTyped,
+// ProcessTypes is called once every file has been processed, before Result.
+type TypedMetric interface {
+	Metric
+	ProcessTypes(pkg *types.Package, info *types.Info)
+}
+
+// Describer is a Metric that explains what it measures and how to read its
+// result, as printed with --explain.
+type Describer interface {
+	Metric
+	Description() string
+}
+
+// Versioned is a Metric with a version, raised whenever a change to the
+// metric changes its result for the same files, so that results cached by an
+// earlier version aren't reused. A Metric that doesn't implement it is at
+// version 0.
+type Versioned interface {
+	Metric
+	Version() int
+}
+
+// Version returns the version of m, 0 unless it implements Versioned.
+func Version(m Metric) int {
+	if v, ok := m.(Versioned); ok {
+		return v.Version()
+	}
+	return 0
+}
+
+// FileMetric is a Metric whose result for a package follows from its result
+// for each file alone, so that it can be computed, and cached, file by file.
+// Merge is passed the Result of a fresh FileMetric for each file, in the
+// order the files are analysed, and leaves the metric as if it had processed
+// them all, Result being called next.
+type FileMetric interface {
+	Metric
+	Merge(results []interface{})
+}
+
+// Cache holds the results of metrics computed by AnalyseCached, keyed by the
+// names of the files they were computed from. A FileMetric is stored for
+// each file, any other metric for the files of the package.
+type Cache interface {
+	// Load returns the result of m over files, reporting false if it isn't
+	// cached. m is a fresh metric, the Result of which has the type of
+	// the result to return.
+	Load(m Metric, files []string) (interface{}, bool)
+	// Store caches the result of m over files.
+	Store(m Metric, files []string, result interface{})
+}
+
+var registry = []func() Metric{}
+
+// Register makes a metric available to Analyse. newMetric is called to create
+// a fresh Metric for every package analysed. Register panics if a metric with
+// the same name has already been registered.
+func Register(newMetric func() Metric) {
+	name := newMetric().Name()
+	for _, n := range Names() {
+		if n == name {
+			panic(fmt.Sprintf("analyser: metric %q registered twice", name))
+		}
+	}
+	registry = append(registry, newMetric)
+}
+
+// Names returns the names of every registered metric in the order they were
+// registered.
+func Names() []string {
+	out := []string{}
+	for _, m := range Metrics() {
+		out = append(out, m.Name())
+	}
+	return out
+}
+
+// Describe returns the description of the registered metric called name, or
+// "" if it doesn't have one.
+func Describe(name string) string {
+	for _, m := range Metrics() {
+		if d, ok := m.(Describer); ok && m.Name() == name {
+			return d.Description()
+		}
+	}
+	return ""
+}
+
+// Metrics returns a fresh instance of every registered metric in the order
+// they were registered.
+func Metrics() []Metric {
+	out := []Metric{}
+	for _, newMetric := range registry {
+		out = append(out, newMetric())
+	}
+	return out
+}
+
+// Result holds everything gathered about a single package. Metrics maps the
+// name of each registered metric to its result.
+type Result struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	ImportPath string `json:"importPath,omitempty"`
+	Files      int    `json:"files"`
+	Partial    bool   `json:"partial"`
+	// OtherFiles counts the non-Go source files alongside the package,
+	// such as assembly or cgo sources, by extension.
+	OtherFiles map[string]int `json:"otherFiles,omitempty"`
+	// SampledFrom is the number of files in the package when only a sample
+	// of them, Files, was analysed.
+	SampledFrom int `json:"sampledFrom,omitempty"`
+	// Activity is when the files of the package were last changed, if
+	// known.
+	Activity *Activity `json:"activity,omitempty"`
+	// Command is set for a package main that declares func main.
+	Command bool `json:"command"`
+	// Typed is set when the package was type checked, so TypedMetrics made
+	// use of type information.
+	Typed   bool                   `json:"typed,omitempty"`
+	Metrics map[string]interface{} `json:"metrics"`
+	// Findings are the potential problems found by the heuristic checks
+	// among the metrics.
+	Findings []Finding `json:"findings"`
+}
+
+// Activity is the most and least recently changed files of a package.
+type Activity struct {
+	Newest FileDate `json:"newest"`
+	Oldest FileDate `json:"oldest"`
+}
+
+// FileDate is when a file was last changed.
+type FileDate struct {
+	File string    `json:"file"`
+	Date time.Time `json:"date"`
+}
+
+// Analyse runs every registered metric over the files of a single package.
+func Analyse(fset *token.FileSet, files []*ast.File) Result {
+	return AnalyseTyped(fset, files, nil, nil)
+}
+
+// AnalyseTyped is Analyse for a package that has been type checked, passing
+// pkg and info to every TypedMetric. With a nil pkg it's the same as Analyse.
+func AnalyseTyped(fset *token.FileSet, files []*ast.File, pkg *types.Package, info *types.Info) Result {
+	r := Result{Files: len(files), Typed: pkg != nil, Metrics: map[string]interface{}{}}
+	if len(files) > 0 {
+		r.Name = files[0].Name.Name
+	}
+
+	metrics := Metrics()
+	for _, f := range files {
+		if f.Name.Name == "main" && declaresMain(f) {
+			r.Command = true
+		}
+		for _, m := range metrics {
+			m.Process(fset, f)
+		}
+	}
+	for _, m := range metrics {
+		if tm, ok := m.(TypedMetric); ok && pkg != nil {
+			tm.ProcessTypes(pkg, info)
+		}
+		r.Metrics[m.Name()] = m.Result()
+	}
+	r.Findings = findings(r)
+
+	return r
+}
+
+// AnalyseCached is Analyse reusing the results held in c and storing those
+// it computes. A FileMetric is run file by file, so that only the files
+// missing from c are processed; any other metric is run over every file
+// unless c holds its result for exactly these.
+func AnalyseCached(fset *token.FileSet, files []*ast.File, c Cache) Result {
+	r := Result{Files: len(files), Metrics: map[string]interface{}{}}
+	if len(files) > 0 {
+		r.Name = files[0].Name.Name
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if f.Name.Name == "main" && declaresMain(f) {
+			r.Command = true
+		}
+		names = append(names, fset.File(f.Pos()).Name())
+	}
+	for _, newMetric := range registry {
+		m := newMetric()
+		r.Metrics[m.Name()] = cachedResult(newMetric, fset, files, names, c)
+	}
+	r.Findings = findings(r)
+
+	return r
+}
+
+// cachedResult returns the result of the metric newMetric creates over files,
+// named names, computing and storing in c whatever it doesn't hold.
+func cachedResult(newMetric func() Metric, fset *token.FileSet, files []*ast.File, names []string, c Cache) interface{} {
+	m := newMetric()
+	fm, ok := m.(FileMetric)
+	if !ok {
+		if res, ok := c.Load(m, names); ok {
+			return res
+		}
+		for _, f := range files {
+			m.Process(fset, f)
+		}
+		res := m.Result()
+		c.Store(m, names, res)
+		return res
+	}
+
+	results := []interface{}{}
+	for i, f := range files {
+		file := names[i : i+1]
+		res, ok := c.Load(newMetric(), file)
+		if !ok {
+			one := newMetric()
+			one.Process(fset, f)
+			res = one.Result()
+			c.Store(one, file, res)
+		}
+		results = append(results, res)
+	}
+	fm.Merge(results)
+	return fm.Result()
+}
+
+// declaresMain reports whether f declares a plain func main.
+func declaresMain(f *ast.File) bool {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings gathers the Warnings reported by every metric of the result.
+func (r Result) Warnings() []Warning {
+	out := []Warning{}
+	for _, name := range Names() {
+		if ws, ok := r.Metrics[name].([]Warning); ok {
+			out = append(out, ws...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// APISymbol is an exported symbol of a package along with its signature, so
+// that changes to the symbol can be detected.
+type APISymbol struct {
+	// Name is qualified by the receiver type for methods.
+	Name string `json:"name"`
+	// Kind is func, method, type, const or var.
+	Kind      string `json:"kind"`
+	Signature string `json:"signature"`
+}
+
+// api collects the exported API of a package.
+type api struct {
+	symbols []APISymbol
+}
+
+func (m *api) Name() string { return APIMetric }
+
+func (m *api) Description() string {
+	return "The exported symbols and their signatures, for spotting changes to the API between versions."
+}
+
+func (m *api) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil {
+				m.symbols = append(m.symbols, APISymbol{Name: d.Name.Name, Kind: "func", Signature: types.ExprString(d.Type)})
+			} else if ast.IsExported(receiverName(d)) {
+				m.symbols = append(m.symbols, APISymbol{Name: funcName(d), Kind: "method", Signature: types.ExprString(d.Type)})
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						m.symbols = append(m.symbols, APISymbol{Name: s.Name.Name, Kind: "type", Signature: typeSignature(s)})
+					}
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					for i, name := range s.Names {
+						if !name.IsExported() {
+							continue
+						}
+						sig := ""
+						if s.Type != nil {
+							sig = types.ExprString(s.Type)
+						}
+						if kind == "const" && i < len(s.Values) {
+							// the value of a constant is part of its API
+							sig = strings.TrimSpace(sig + " = " + types.ExprString(s.Values[i]))
+						}
+						m.symbols = append(m.symbols, APISymbol{Name: name.Name, Kind: kind, Signature: sig})
+					}
+				}
+			}
+		}
+	}
+}
+
+// Result returns the symbols sorted by name, those of the same name in
+// source order so merging the results of each file sorts them alike.
+func (m *api) Result() interface{} {
+	out := append([]APISymbol{}, m.symbols...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (m *api) Merge(results []interface{}) {
+	for _, r := range results {
+		m.symbols = append(m.symbols, r.([]APISymbol)...)
+	}
+}
+
+// funcSignature renders the full declaration of a function or method
+// without its body, such as func (c *Client) Get(ctx context.Context, url
+// string) (*Response, error).
+func funcSignature(fn *ast.FuncDecl) string {
+	recv := ""
+	if fn.Recv != nil {
+		recv = "(" + strings.TrimSuffix(strings.TrimPrefix(types.ExprString(&ast.FuncType{Params: fn.Recv}), "func("), ")") + ") "
+	}
+	return "func " + recv + fn.Name.Name + typeParams(fn.Type.TypeParams) + strings.TrimPrefix(types.ExprString(fn.Type), "func")
+}
+
+// typeParams renders a list of type parameters, such as [K comparable, V
+// any], which types.ExprString leaves out of declarations.
+func typeParams(fl *ast.FieldList) string {
+	if fl == nil {
+		return ""
+	}
+	params := []string{}
+	for _, p := range fl.List {
+		names := []string{}
+		for _, name := range p.Names {
+			names = append(names, name.Name)
+		}
+		params = append(params, strings.Join(names, ", ")+" "+types.ExprString(p.Type))
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// typeSignature renders a type declaration, listing only the exported fields
+// of a struct and the exported methods of an interface, as unexported ones
+// aren't part of the API.
+func typeSignature(s *ast.TypeSpec) string {
+	prefix := ""
+	if s.TypeParams != nil {
+		prefix = typeParams(s.TypeParams) + " "
+	}
+	if s.Assign.IsValid() {
+		prefix += "= "
+	}
+
+	var fields *ast.FieldList
+	keyword := ""
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		fields, keyword = t.Fields, "struct"
+	case *ast.InterfaceType:
+		fields, keyword = t.Methods, "interface"
+	default:
+		return prefix + types.ExprString(s.Type)
+	}
+
+	parts := []string{}
+	for _, field := range fields.List {
+		typ := types.ExprString(field.Type)
+		if keyword == "interface" {
+			if ft, ok := field.Type.(*ast.FuncType); ok {
+				typ = types.ExprString(ft)[len("func"):]
+			}
+		}
+		if len(field.Names) == 0 {
+			// embedded types promote their fields and methods
+			parts = append(parts, typ)
+			continue
+		}
+		for _, name := range field.Names {
+			if name.IsExported() {
+				sep := " "
+				if keyword == "interface" {
+					sep = ""
+				}
+				parts = append(parts, name.Name+sep+typ)
+			}
+		}
+	}
+	return prefix + keyword + "{" + strings.Join(parts, "; ") + "}"
+}
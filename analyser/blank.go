@@ -0,0 +1,218 @@
+package analyser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// blankAssigns finds the assignments discarding a value into the blank
+// identifier, such as _ = x or v, _ := f(). Frequent ones can point to
+// ignored errors or placeholder code. The comma ok idiom, v, _ := m[k], only
+// discards whether the value was found and isn't counted.
+type blankAssigns struct {
+	sites []FuncPos
+}
+
+func (m *blankAssigns) Name() string { return BlankAssignsMetric }
+
+func (m *blankAssigns) Description() string {
+	return "Assignments to the blank identifier. Frequent ones may be ignored errors or placeholder code."
+}
+
+func (m *blankAssigns) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			as, ok := n.(*ast.AssignStmt)
+			if !ok || isCommaOk(as) {
+				return true
+			}
+			for _, lhs := range as.Lhs {
+				if isBlank(lhs) {
+					m.sites = append(m.sites, FuncPos{Func: funcName(fn), Pos: fset.Position(as.Pos())})
+					break
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Result returns where each assignment to _ is, in source order.
+func (m *blankAssigns) Result() interface{} {
+	return append([]FuncPos{}, m.sites...)
+}
+
+func (m *blankAssigns) Merge(results []interface{}) {
+	for _, r := range results {
+		m.sites = append(m.sites, r.([]FuncPos)...)
+	}
+}
+
+// isCommaOk reports whether as is a v, _ = x form that only discards the ok
+// of a type assertion, map index or channel receive.
+func isCommaOk(as *ast.AssignStmt) bool {
+	if len(as.Lhs) != 2 || len(as.Rhs) != 1 || isBlank(as.Lhs[0]) {
+		return false
+	}
+	rhs := as.Rhs[0]
+	for {
+		p, ok := rhs.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		rhs = p.X
+	}
+	switch rhs := rhs.(type) {
+	case *ast.TypeAssertExpr, *ast.IndexExpr:
+		return true
+	case *ast.UnaryExpr:
+		return rhs.Op == token.ARROW
+	}
+	return false
+}
+
+func isBlank(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// discardedCall is a call whose last result, if any, is thrown away: either
+// assigned to _ or dropped by calling it as a statement.
+type discardedCall struct {
+	call     *ast.CallExpr
+	assigned bool
+	pos      token.Position
+}
+
+// ignoredErrors flags calls whose error result is discarded. With only the
+// AST, just calls of the package's own functions declaring an error as
+// their last result can be recognised; given type information every call
+// is checked. Printing with fmt and writing to a bytes.Buffer or
+// strings.Builder are left out, their errors being ignored by convention.
+type ignoredErrors struct {
+	calls []discardedCall
+	// returnsError records, for each package level function, whether its
+	// last result is an error.
+	returnsError map[string]bool
+	// typed is set by ProcessTypes and replaces what is found from the AST.
+	typed []Warning
+}
+
+func newIgnoredErrors() *ignoredErrors {
+	return &ignoredErrors{returnsError: map[string]bool{}}
+}
+
+func (m *ignoredErrors) Name() string { return IgnoredErrorsMetric }
+
+func (m *ignoredErrors) Description() string {
+	return "Calls whose error result is assigned to _ or dropped. Each hides a failure; handle it, or document why it can't happen."
+}
+
+func (m *ignoredErrors) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if fn.Recv == nil {
+			results := fn.Type.Results
+			last := results.NumFields() > 0 && types.ExprString(results.List[len(results.List)-1].Type) == "error"
+			m.returnsError[fn.Name.Name] = last
+		}
+		if fn.Body == nil {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch s := n.(type) {
+			case *ast.ExprStmt:
+				if call, ok := s.X.(*ast.CallExpr); ok {
+					m.calls = append(m.calls, discardedCall{call: call, pos: fset.Position(call.Pos())})
+				}
+			case *ast.AssignStmt:
+				if len(s.Rhs) != 1 || !isBlank(s.Lhs[len(s.Lhs)-1]) {
+					return true
+				}
+				if call, ok := s.Rhs[0].(*ast.CallExpr); ok {
+					m.calls = append(m.calls, discardedCall{call: call, assigned: true, pos: fset.Position(call.Pos())})
+				}
+			}
+			return true
+		})
+	}
+}
+
+// ProcessTypes checks the result of every discarded call against the error
+// type.
+func (m *ignoredErrors) ProcessTypes(pkg *types.Package, info *types.Info) {
+	errType := types.Universe.Lookup("error").Type()
+	m.typed = []Warning{}
+	for _, c := range m.calls {
+		tv, ok := info.Types[c.call]
+		if !ok || excludedCall(info, c.call) {
+			continue
+		}
+		last := tv.Type
+		if tuple, ok := last.(*types.Tuple); ok {
+			if tuple.Len() == 0 {
+				continue
+			}
+			last = tuple.At(tuple.Len() - 1).Type()
+		}
+		if last != nil && types.Identical(last, errType) {
+			m.typed = append(m.typed, c.warning())
+		}
+	}
+}
+
+// excludedCall reports whether call prints with fmt or writes to a
+// bytes.Buffer or strings.Builder.
+func excludedCall(info *types.Info, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if s, ok := info.Selections[sel]; ok {
+		recv := types.TypeString(s.Recv(), nil)
+		recv = strings.TrimPrefix(recv, "*")
+		return recv == "bytes.Buffer" || recv == "strings.Builder"
+	}
+	obj := info.Uses[sel.Sel]
+	if obj == nil || obj.Pkg() == nil {
+		return false
+	}
+	return obj.Pkg().Path() == "fmt" && (strings.HasPrefix(obj.Name(), "Print") || strings.HasPrefix(obj.Name(), "Fprint"))
+}
+
+func (c discardedCall) warning() Warning {
+	how := "dropped"
+	if c.assigned {
+		how = "assigned to _"
+	}
+	return Warning{
+		Check:   "ignored-error",
+		Pos:     c.pos,
+		Message: fmt.Sprintf("error returned by %s is %s", types.ExprString(c.call.Fun), how),
+	}
+}
+
+// Result returns a Warning for every call discarding an error, in source
+// order.
+func (m *ignoredErrors) Result() interface{} {
+	if m.typed != nil {
+		return append([]Warning{}, m.typed...)
+	}
+	out := []Warning{}
+	for _, c := range m.calls {
+		if id, ok := c.call.Fun.(*ast.Ident); ok && (id.Obj == nil || id.Obj.Kind == ast.Fun) && m.returnsError[id.Name] {
+			out = append(out, c.warning())
+		}
+	}
+	return out
+}
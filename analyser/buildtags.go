@@ -0,0 +1,79 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/token"
+)
+
+// buildTags counts the files guarded by each build tag, taken from the
+// //go:build line of a file or, failing that, its // +build lines.
+type buildTags struct {
+	files map[string]int
+}
+
+func (m *buildTags) Name() string { return BuildTagsMetric }
+
+func (m *buildTags) Description() string {
+	return "Files guarded by each build tag. Many tags mean code that only some builds compile and test."
+}
+
+func (m *buildTags) Process(fset *token.FileSet, f *ast.File) {
+	var goBuild constraint.Expr
+	plusBuild := []constraint.Expr{}
+	for _, c := range f.Comments {
+		if c.Pos() > f.Package {
+			// constraints must come before the package clause
+			break
+		}
+		for _, line := range c.List {
+			expr, err := constraint.Parse(line.Text)
+			if err != nil {
+				continue
+			}
+			if constraint.IsGoBuild(line.Text) {
+				goBuild = expr
+			} else {
+				plusBuild = append(plusBuild, expr)
+			}
+		}
+	}
+
+	exprs := plusBuild
+	if goBuild != nil {
+		exprs = []constraint.Expr{goBuild}
+	}
+	tags := map[string]bool{}
+	for _, e := range exprs {
+		collectTags(e, tags)
+	}
+	for tag := range tags {
+		m.files[tag]++
+	}
+}
+
+// Result returns the number of files each tag guards.
+func (m *buildTags) Result() interface{} { return m.files }
+
+func (m *buildTags) Merge(results []interface{}) {
+	for _, r := range results {
+		for k, n := range r.(map[string]int) {
+			m.files[k] += n
+		}
+	}
+}
+
+func collectTags(e constraint.Expr, tags map[string]bool) {
+	switch e := e.(type) {
+	case *constraint.TagExpr:
+		tags[e.Tag] = true
+	case *constraint.NotExpr:
+		collectTags(e.X, tags)
+	case *constraint.AndExpr:
+		collectTags(e.X, tags)
+		collectTags(e.Y, tags)
+	case *constraint.OrExpr:
+		collectTags(e.X, tags)
+		collectTags(e.Y, tags)
+	}
+}
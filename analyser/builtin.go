@@ -0,0 +1,88 @@
+package analyser
+
+// Names of the built-in metrics, used as keys of Result.Metrics.
+const (
+	ExportedFuncsMetric   = "exported-funcs"
+	ImportsMetric         = "imports"
+	FileLinesMetric       = "file-lines"
+	PlatformsMetric       = "platforms"
+	RiskyPackagesMetric   = "risky-packages"
+	UnreferencedMetric    = "unreferenced"
+	TypesMetric           = "types"
+	ShadowMetric          = "shadow"
+	BareReceivesMetric    = "bare-receives"
+	ComplexityMetric      = "complexity"
+	HalsteadMetric        = "halstead"
+	MaintainabilityMetric = "maintainability"
+	FanOutMetric          = "fan-out"
+	StubsMetric           = "stubs"
+	MultipleReturnsMetric = "multiple-returns"
+	EmptyInterfaceMetric  = "empty-interface"
+	DocsMetric            = "docs"
+	MethodNamesMetric     = "method-names"
+	ConstructorsMetric    = "constructors"
+	APIMetric             = "api"
+	ControlFlowMetric     = "control-flow"
+	BuildTagsMetric       = "build-tags"
+	MutableMetric         = "mutable-signatures"
+	ImplementationsMetric = "implementations"
+	VariadicMetric        = "variadic"
+	ImportsPerFileMetric  = "imports-per-file"
+	StructTagsMetric      = "struct-tags"
+	OptionsMetric         = "functional-options"
+	SignaturesMetric      = "signatures"
+	GenericsMetric        = "generics"
+	SecretsMetric         = "secrets"
+	ErrorWrappingMetric   = "error-wrapping"
+	ReceiversMetric       = "receivers"
+	CallGraphMetric       = "call-graph"
+	FuncLinesMetric       = "func-lines"
+	BlankAssignsMetric    = "blank-assigns"
+	IgnoredErrorsMetric   = "ignored-errors"
+	SliceParamsMetric     = "slice-params"
+	LargeParamsMetric     = "large-value-params"
+	NameVerbsMetric       = "name-verbs"
+)
+
+func init() {
+	Register(func() Metric { return &exportedFuncs{} })
+	Register(func() Metric { return &imports{seen: map[string]bool{}} })
+	Register(func() Metric { return &fileLines{} })
+	Register(func() Metric { return &platforms{counts: map[string]int{}} })
+	Register(func() Metric { return newRiskyPackages() })
+	Register(func() Metric { return newUnreferenced() })
+	Register(func() Metric { return newExportedTypes() })
+	Register(func() Metric { return &shadow{} })
+	Register(func() Metric { return &bareReceives{} })
+	Register(func() Metric { return &complexity{} })
+	Register(func() Metric { return &halstead{c: newHalsteadCounter()} })
+	Register(func() Metric { return &maintainability{} })
+	Register(func() Metric { return &fanOut{} })
+	Register(func() Metric { return &stubs{} })
+	Register(func() Metric { return &multipleReturns{} })
+	Register(func() Metric { return &emptyInterfaces{} })
+	Register(func() Metric { return &docs{} })
+	Register(func() Metric { return newMethodNames() })
+	Register(func() Metric { return newConstructors() })
+	Register(func() Metric { return &api{} })
+	Register(func() Metric { return &controlFlow{} })
+	Register(func() Metric { return &buildTags{files: map[string]int{}} })
+	Register(func() Metric { return &mutableSignatures{} })
+	Register(func() Metric { return newImplementations() })
+	Register(func() Metric { return &variadic{} })
+	Register(func() Metric { return &importsPerFile{} })
+	Register(func() Metric { return &structTags{counts: map[string]int{}} })
+	Register(func() Metric { return newFunctionalOptions() })
+	Register(func() Metric { return &signatures{} })
+	Register(func() Metric { return &generics{} })
+	Register(func() Metric { return &secrets{} })
+	Register(func() Metric { return &errorWrapping{} })
+	Register(func() Metric { return newReceivers() })
+	Register(func() Metric { return newCallGraph() })
+	Register(func() Metric { return &funcLengths{} })
+	Register(func() Metric { return &blankAssigns{} })
+	Register(func() Metric { return newIgnoredErrors() })
+	Register(func() Metric { return &sliceParams{} })
+	Register(func() Metric { return newLargeParams() })
+	Register(func() Metric { return newNameVerbs() })
+}
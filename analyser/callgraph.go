@@ -0,0 +1,102 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// Call is an edge of a CallGraph, from a function to one it calls.
+type Call struct {
+	Caller string `json:"caller"`
+	Callee string `json:"callee"`
+}
+
+// CallGraph holds the functions and methods declared in a package, named as
+// Func or Type.Method, and the calls between them.
+type CallGraph struct {
+	Funcs []string `json:"funcs"`
+	Calls []Call   `json:"calls"`
+}
+
+// callGraph finds the calls between the functions of a package. Without type
+// information, only calls that can be resolved by name are followed: those
+// of a package level function by its identifier, and those of a method
+// through the receiver of the method making the call. Calls made by
+// function literals are attributed to the function declaring them.
+type callGraph struct {
+	funcs map[string]bool
+	// calls are only resolved once every file has been processed, as the
+	// callee may be declared in a later one.
+	calls map[Call]bool
+}
+
+func newCallGraph() *callGraph {
+	return &callGraph{funcs: map[string]bool{}, calls: map[Call]bool{}}
+}
+
+func (m *callGraph) Name() string { return CallGraphMetric }
+
+func (m *callGraph) Description() string {
+	return "Calls between the functions of the package, resolved by name. Functions many others call are the core of the package; those calling many are orchestrators."
+}
+
+func (m *callGraph) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		caller := funcName(fn)
+		m.funcs[caller] = true
+		if fn.Body == nil {
+			continue
+		}
+
+		recvType, recvVar := receiverName(fn), ""
+		if recvType != "" && len(fn.Recv.List[0].Names) > 0 {
+			recvVar = fn.Recv.List[0].Names[0].Name
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				// unresolved identifiers are declared in another file
+				if fun.Obj == nil || fun.Obj.Kind == ast.Fun {
+					m.calls[Call{Caller: caller, Callee: fun.Name}] = true
+				}
+			case *ast.SelectorExpr:
+				if x, ok := fun.X.(*ast.Ident); ok && recvVar != "" && x.Name == recvVar {
+					m.calls[Call{Caller: caller, Callee: recvType + "." + fun.Sel.Name}] = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Result returns the CallGraph with its functions and calls sorted. Calls to
+// anything not declared in the package, such as builtins or fields holding
+// functions, are dropped.
+func (m *callGraph) Result() interface{} {
+	g := CallGraph{Funcs: []string{}, Calls: []Call{}}
+	for name := range m.funcs {
+		g.Funcs = append(g.Funcs, name)
+	}
+	sort.Strings(g.Funcs)
+	for c := range m.calls {
+		if m.funcs[c.Callee] {
+			g.Calls = append(g.Calls, c)
+		}
+	}
+	sort.Slice(g.Calls, func(i, j int) bool {
+		if g.Calls[i].Caller != g.Calls[j].Caller {
+			return g.Calls[i].Caller < g.Calls[j].Caller
+		}
+		return g.Calls[i].Callee < g.Calls[j].Callee
+	})
+	return g
+}
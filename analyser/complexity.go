@@ -0,0 +1,111 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// FuncComplexity is the cyclomatic complexity of a function.
+type FuncComplexity struct {
+	Func       string         `json:"func"`
+	Pos        token.Position `json:"position"`
+	Complexity int            `json:"complexity"`
+}
+
+// FileComplexity is the total cyclomatic complexity of the functions of a
+// file.
+type FileComplexity struct {
+	File       string `json:"file"`
+	Complexity int    `json:"complexity"`
+	Funcs      int    `json:"funcs"`
+}
+
+// ByFileComplexity sums the complexity of funcs by file and returns the files,
+// the most complex first. A file of many simple functions can need more care
+// than one holding the most complex function.
+func ByFileComplexity(funcs []FuncComplexity) []FileComplexity {
+	out := []FileComplexity{}
+	index := map[string]int{}
+	for _, f := range funcs {
+		i, ok := index[f.Pos.Filename]
+		if !ok {
+			i = len(out)
+			index[f.Pos.Filename] = i
+			out = append(out, FileComplexity{File: f.Pos.Filename})
+		}
+		out[i].Complexity += f.Complexity
+		out[i].Funcs++
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Complexity > out[j].Complexity })
+	return out
+}
+
+// complexity computes the cyclomatic complexity of every function.
+type complexity struct {
+	funcs []FuncComplexity
+}
+
+func (m *complexity) Name() string { return ComplexityMetric }
+
+func (m *complexity) Description() string {
+	return "Cyclomatic complexity of each function, the number of paths through it. Above 10 is hard to test and worth splitting."
+}
+
+func (m *complexity) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil {
+			m.funcs = append(m.funcs, FuncComplexity{
+				Func:       funcName(fn),
+				Pos:        fset.Position(fn.Pos()),
+				Complexity: cyclomatic(fn.Body),
+			})
+		}
+	}
+}
+
+// Result returns the FuncComplexity of every function in source order.
+func (m *complexity) Result() interface{} {
+	return append([]FuncComplexity{}, m.funcs...)
+}
+
+func (m *complexity) Merge(results []interface{}) {
+	for _, r := range results {
+		m.funcs = append(m.funcs, r.([]FuncComplexity)...)
+	}
+}
+
+// cyclomatic returns one more than the number of decision points in n: each
+// if, for, range, non-default case and && or || adds a path through the code.
+func cyclomatic(n ast.Node) int {
+	c := 1
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			c++
+		case *ast.CaseClause:
+			if n.List != nil {
+				c++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				c++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c++
+			}
+		}
+		return true
+	})
+	return c
+}
+
+// funcName returns the name of a function, qualified by its receiver's type
+// for methods.
+func funcName(fn *ast.FuncDecl) string {
+	if recv := receiverName(fn); recv != "" {
+		return recv + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
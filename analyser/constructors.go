@@ -0,0 +1,94 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// Constructor is a New function along with the type it constructs.
+type Constructor struct {
+	Func string `json:"func"`
+	Type string `json:"type"`
+}
+
+// constructors finds the exported functions named New* that return a type
+// declared in the package, possibly by pointer.
+type constructors struct {
+	declared   map[string]bool
+	candidates []Constructor
+}
+
+func newConstructors() *constructors {
+	return &constructors{declared: map[string]bool{}}
+}
+
+func (m *constructors) Name() string { return ConstructorsMetric }
+
+func (m *constructors) Description() string {
+	return "Exported New functions and the types they construct. Types without one may be meant to be used as zero values."
+}
+
+func (m *constructors) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				if ts, ok := s.(*ast.TypeSpec); ok {
+					m.declared[ts.Name.Name] = true
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv != nil || !d.Name.IsExported() || !hasPrefixWord(d.Name.Name, "New") || d.Type.Results.NumFields() == 0 {
+				continue
+			}
+			if t := namedType(d.Type.Results.List[0].Type); t != "" {
+				m.candidates = append(m.candidates, Constructor{Func: d.Name.Name, Type: t})
+			}
+		}
+	}
+}
+
+// Result returns the constructors sorted by name. Types are only known once
+// every file has been processed, so candidates are filtered here.
+func (m *constructors) Result() interface{} {
+	out := []Constructor{}
+	for _, c := range m.candidates {
+		if m.declared[c.Type] {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Func < out[j].Func })
+	return out
+}
+
+// hasPrefixWord reports whether name is prefix or starts with prefix
+// followed by a new word, so that News isn't taken for New.
+func hasPrefixWord(name, prefix string) bool {
+	if len(name) < len(prefix) || name[:len(prefix)] != prefix {
+		return false
+	}
+	rest := name[len(prefix):]
+	return rest == "" || (rest[0] >= 'A' && rest[0] <= 'Z') || rest[0] == '_' || (rest[0] >= '0' && rest[0] <= '9')
+}
+
+// namedType returns the name of a type expression, dereferencing pointers
+// and dropping type arguments, or "" if it isn't a named local type.
+func namedType(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Jump is a labelled statement, goto, or break or continue to a label.
+type Jump struct {
+	// Kind is label, goto, break or continue.
+	Kind  string         `json:"kind"`
+	Label string         `json:"label"`
+	Pos   token.Position `json:"position"`
+}
+
+// ControlFlow counts the labels and jumps to them in a package, which tend
+// to make control flow harder to follow, and the defer statements of its
+// functions, whose order of execution can surprise.
+type ControlFlow struct {
+	Labels    int    `json:"labels"`
+	Gotos     int    `json:"gotos"`
+	Breaks    int    `json:"breaks"`
+	Continues int    `json:"continues"`
+	Jumps     []Jump `json:"jumps"`
+	// Defers is the number of defer statements across Funcs functions,
+	// counting those in function literals towards the function declaring
+	// them. MostDefers is the function with the most.
+	Defers     int        `json:"defers"`
+	Funcs      int        `json:"funcs"`
+	MostDefers DeferCount `json:"mostDefers"`
+}
+
+// DeferCount is the number of defer statements in a function.
+type DeferCount struct {
+	Func   string `json:"func"`
+	Defers int    `json:"defers"`
+}
+
+// DefersPerFunc returns the mean number of defer statements per function.
+func (c ControlFlow) DefersPerFunc() float64 {
+	if c.Funcs == 0 {
+		return 0
+	}
+	return float64(c.Defers) / float64(c.Funcs)
+}
+
+type controlFlow struct {
+	r ControlFlow
+}
+
+func (m *controlFlow) Name() string { return ControlFlowMetric }
+
+func (m *controlFlow) Description() string {
+	return "How often functions branch, loop and defer. Heavy use of goto or labelled breaks makes control flow hard to follow."
+}
+
+func (m *controlFlow) Process(fset *token.FileSet, f *ast.File) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.LabeledStmt:
+			m.r.Labels++
+			m.r.Jumps = append(m.r.Jumps, Jump{Kind: "label", Label: n.Label.Name, Pos: fset.Position(n.Pos())})
+		case *ast.BranchStmt:
+			if n.Label == nil {
+				return true
+			}
+			switch n.Tok {
+			case token.GOTO:
+				m.r.Gotos++
+			case token.BREAK:
+				m.r.Breaks++
+			case token.CONTINUE:
+				m.r.Continues++
+			default:
+				return true
+			}
+			m.r.Jumps = append(m.r.Jumps, Jump{Kind: n.Tok.String(), Label: n.Label.Name, Pos: fset.Position(n.Pos())})
+		}
+		return true
+	})
+
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		defers := 0
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.DeferStmt); ok {
+				defers++
+			}
+			return true
+		})
+		m.r.Funcs++
+		m.r.Defers += defers
+		if defers > m.r.MostDefers.Defers {
+			m.r.MostDefers = DeferCount{Func: funcName(fn), Defers: defers}
+		}
+	}
+}
+
+// Result returns the ControlFlow of the package, jumps in source order.
+func (m *controlFlow) Result() interface{} {
+	r := m.r
+	r.Jumps = append([]Jump{}, m.r.Jumps...)
+	return r
+}
+
+func (m *controlFlow) Merge(results []interface{}) {
+	for _, r := range results {
+		c := r.(ControlFlow)
+		m.r.Labels += c.Labels
+		m.r.Gotos += c.Gotos
+		m.r.Breaks += c.Breaks
+		m.r.Continues += c.Continues
+		m.r.Jumps = append(m.r.Jumps, c.Jumps...)
+		m.r.Defers += c.Defers
+		m.r.Funcs += c.Funcs
+		if c.MostDefers.Defers > m.r.MostDefers.Defers {
+			m.r.MostDefers = c.MostDefers
+		}
+	}
+}
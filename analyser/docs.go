@@ -0,0 +1,156 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Symbol is an exported identifier declared at the top level of a package.
+type Symbol struct {
+	Name string `json:"name"`
+	// Kind is func, method, type, const or var.
+	Kind string         `json:"kind"`
+	Pos  token.Position `json:"position"`
+}
+
+// DocCoverage is how many of the exported symbols of a package have a doc
+// comment.
+type DocCoverage struct {
+	Exported     int      `json:"exported"`
+	Undocumented []Symbol `json:"undocumented"`
+	// AverageWords is the mean length of the doc comments found.
+	AverageWords float64 `json:"averageWords"`
+	// Terse are the documented symbols whose doc says little beyond
+	// restating the name.
+	Terse []Symbol `json:"terse"`
+}
+
+// Percent returns the percentage of exported symbols that are documented,
+// 100 when there are none.
+func (d DocCoverage) Percent() float64 {
+	if d.Exported == 0 {
+		return 100
+	}
+	return 100 * float64(d.Exported-len(d.Undocumented)) / float64(d.Exported)
+}
+
+// docs finds the exported symbols without a doc comment. A comment on a
+// parenthesised group of declarations documents everything in the group.
+type docs struct {
+	r     DocCoverage
+	words int
+}
+
+func (m *docs) Name() string { return DocsMetric }
+
+func (m *docs) Description() string {
+	return "How many exported symbols have doc comments and how long they are. Low coverage or docs restating the name make an API hard to learn."
+}
+
+func (m *docs) Process(fset *token.FileSet, f *ast.File) {
+	check := func(name string, pos token.Pos, kind string, doc ...*ast.CommentGroup) {
+		m.r.Exported++
+		for _, d := range doc {
+			if d == nil || d.Text() == "" {
+				continue
+			}
+			words := strings.Fields(d.Text())
+			m.words += len(words)
+			if isTerse(name, words) {
+				m.r.Terse = append(m.r.Terse, Symbol{Name: name, Kind: kind, Pos: fset.Position(pos)})
+			}
+			return
+		}
+		m.r.Undocumented = append(m.r.Undocumented, Symbol{Name: name, Kind: kind, Pos: fset.Position(pos)})
+	}
+
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil {
+				check(d.Name.Name, d.Name.Pos(), "func", d.Doc)
+			} else if ast.IsExported(receiverName(d)) {
+				check(funcName(d), d.Name.Pos(), "method", d.Doc)
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						check(s.Name.Name, s.Name.Pos(), "type", s.Doc, d.Doc)
+					}
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					for _, name := range s.Names {
+						if name.IsExported() {
+							check(name.Name, name.Pos(), kind, s.Doc, s.Comment, d.Doc)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Result returns the DocCoverage of the package.
+func (m *docs) Result() interface{} {
+	r := m.r
+	r.Undocumented = append([]Symbol{}, m.r.Undocumented...)
+	r.Terse = append([]Symbol{}, m.r.Terse...)
+	if documented := r.Exported - len(r.Undocumented); documented > 0 {
+		r.AverageWords = float64(m.words) / float64(documented)
+	}
+	return r
+}
+
+// fillerWords carry no meaning of their own in a doc comment.
+var fillerWords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "of": true, "for": true,
+	"to": true, "does": true, "returns": true, "return": true, "new": true, "it": true,
+}
+
+// isTerse reports whether the words of a doc comment are, besides filler,
+// nothing more than the words of the name it documents, such as "Client is
+// a client" or "NewReader returns a new Reader".
+func isTerse(name string, words []string) bool {
+	named := map[string]bool{}
+	for _, part := range strings.Split(name, ".") {
+		named[strings.ToLower(part)] = true
+		for _, w := range splitCamel(part) {
+			named[strings.ToLower(w)] = true
+		}
+	}
+
+	meaningful := 0
+	for _, w := range words {
+		w = strings.ToLower(strings.Trim(w, ".,;:()'\"`"))
+		if w != "" && !named[w] && !fillerWords[w] {
+			meaningful++
+		}
+	}
+	return meaningful < 2
+}
+
+// splitCamel splits an identifier into its words, keeping acronyms together:
+// NewHTTPClient becomes New, HTTP and Client.
+func splitCamel(s string) []string {
+	out := []string{}
+	start := 0
+	for i := 1; i < len(s); i++ {
+		upper := s[i] >= 'A' && s[i] <= 'Z'
+		prevUpper := s[i-1] >= 'A' && s[i-1] <= 'Z'
+		nextLower := i+1 < len(s) && s[i+1] >= 'a' && s[i+1] <= 'z'
+		if upper && (!prevUpper || nextLower) {
+			out = append(out, s[start:i])
+			start = i
+		}
+	}
+	return append(out, s[start:])
+}
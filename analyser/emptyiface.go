@@ -0,0 +1,73 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// FuncPos is a function along with where it is declared.
+type FuncPos struct {
+	Func string         `json:"func"`
+	Pos  token.Position `json:"position"`
+}
+
+// emptyInterfaces finds the exported functions whose parameters or results
+// involve the empty interface, written as interface{} or any, giving up
+// type safety.
+type emptyInterfaces struct {
+	funcs []FuncPos
+}
+
+func (m *emptyInterfaces) Name() string { return EmptyInterfaceMetric }
+
+func (m *emptyInterfaces) Description() string {
+	return "Exported functions taking or returning interface{} or any, which gives up type safety at the API boundary."
+}
+
+func (m *emptyInterfaces) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() {
+			continue
+		}
+		if hasEmptyInterface(fn.Type.Params) || hasEmptyInterface(fn.Type.Results) {
+			m.funcs = append(m.funcs, FuncPos{Func: funcName(fn), Pos: fset.Position(fn.Name.Pos())})
+		}
+	}
+}
+
+// Result returns the functions in the order they were declared.
+func (m *emptyInterfaces) Result() interface{} {
+	return append([]FuncPos{}, m.funcs...)
+}
+
+func (m *emptyInterfaces) Merge(results []interface{}) {
+	for _, r := range results {
+		m.funcs = append(m.funcs, r.([]FuncPos)...)
+	}
+}
+
+// hasEmptyInterface reports whether the type of any field mentions the empty
+// interface, including within composite types such as []interface{}.
+func hasEmptyInterface(fields *ast.FieldList) bool {
+	if fields == nil {
+		return false
+	}
+	found := false
+	for _, field := range fields.List {
+		ast.Inspect(field.Type, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.InterfaceType:
+				if len(n.Methods.List) == 0 {
+					found = true
+				}
+			case *ast.Ident:
+				if n.Name == "any" && n.Obj == nil {
+					found = true
+				}
+			}
+			return !found
+		})
+	}
+	return found
+}
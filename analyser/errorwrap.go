@@ -0,0 +1,87 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// ErrorWrapping counts how errors are created. Calls to fmt.Errorf whose
+// format isn't a string literal are left out, as whether they wrap can't be
+// told.
+type ErrorWrapping struct {
+	// Wrapped and NotWrapped are the fmt.Errorf calls with and without %w.
+	Wrapped    int `json:"wrapped"`
+	NotWrapped int `json:"notWrapped"`
+	ErrorsNew  int `json:"errorsNew"`
+}
+
+// WrapRatio returns the fraction of fmt.Errorf calls that wrap an error, or
+// 0 if there are none.
+func (e ErrorWrapping) WrapRatio() float64 {
+	if e.Wrapped+e.NotWrapped == 0 {
+		return 0
+	}
+	return float64(e.Wrapped) / float64(e.Wrapped+e.NotWrapped)
+}
+
+// errorWrapping finds the calls to fmt.Errorf and errors.New in a package.
+type errorWrapping struct {
+	counts ErrorWrapping
+}
+
+func (m *errorWrapping) Name() string { return ErrorWrappingMetric }
+
+func (m *errorWrapping) Description() string {
+	return "fmt.Errorf calls that wrap with %w against those that don't. Wrapping lets callers inspect the cause with errors.Is and errors.As."
+}
+
+func (m *errorWrapping) Process(fset *token.FileSet, f *ast.File) {
+	fmtName, errorsName := localName(f, "fmt"), localName(f, "errors")
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || pkg.Obj != nil {
+			return true
+		}
+		switch {
+		case pkg.Name == fmtName && sel.Sel.Name == "Errorf" && len(call.Args) > 0:
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			format, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+			if strings.Contains(format, "%w") {
+				m.counts.Wrapped++
+			} else {
+				m.counts.NotWrapped++
+			}
+		case pkg.Name == errorsName && sel.Sel.Name == "New":
+			m.counts.ErrorsNew++
+		}
+		return true
+	})
+}
+
+// Result returns the ErrorWrapping of the package.
+func (m *errorWrapping) Result() interface{} { return m.counts }
+
+func (m *errorWrapping) Merge(results []interface{}) {
+	for _, r := range results {
+		e := r.(ErrorWrapping)
+		m.counts.Wrapped += e.Wrapped
+		m.counts.NotWrapped += e.NotWrapped
+		m.counts.ErrorsNew += e.ErrorsNew
+	}
+}
@@ -0,0 +1,50 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// ExportedFuncs counts the exported functions and methods of a package.
+type ExportedFuncs struct {
+	Total   int       `json:"total"`
+	PerFile []float64 `json:"perFile"`
+	// OneLiners counts those whose body is a single statement, such as
+	// accessors and thin wrappers.
+	OneLiners int `json:"oneLiners"`
+}
+
+type exportedFuncs struct {
+	r ExportedFuncs
+}
+
+func (m *exportedFuncs) Name() string { return ExportedFuncsMetric }
+
+func (m *exportedFuncs) Description() string {
+	return "Exported functions per file. A few files holding most of them suggests the API is concentrated, or files that should be split."
+}
+
+func (m *exportedFuncs) Process(fset *token.FileSet, f *ast.File) {
+	publicFuncsPerFile := 0.
+	for _, d := range f.Decls {
+		if fn, isFn := d.(*ast.FuncDecl); isFn && ast.IsExported(fn.Name.Name) {
+			m.r.Total++
+			publicFuncsPerFile++
+			if fn.Body != nil && len(fn.Body.List) == 1 {
+				m.r.OneLiners++
+			}
+		}
+	}
+	m.r.PerFile = append(m.r.PerFile, publicFuncsPerFile)
+}
+
+func (m *exportedFuncs) Result() interface{} { return m.r }
+
+func (m *exportedFuncs) Merge(results []interface{}) {
+	for _, r := range results {
+		e := r.(ExportedFuncs)
+		m.r.Total += e.Total
+		m.r.PerFile = append(m.r.PerFile, e.PerFile...)
+		m.r.OneLiners += e.OneLiners
+	}
+}
@@ -0,0 +1,86 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// builtins are the predeclared functions, which aren't counted as fan-out.
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true, "max": true,
+	"min": true, "new": true, "panic": true, "print": true, "println": true,
+	"real": true, "recover": true,
+}
+
+// FuncFanOut is the number of distinct functions a function calls.
+type FuncFanOut struct {
+	Func   string `json:"func"`
+	FanOut int    `json:"fanOut"`
+}
+
+// FanOut summarises how many distinct functions the functions of a package
+// call.
+type FanOut struct {
+	Average float64      `json:"average"`
+	Highest FuncFanOut   `json:"highest"`
+	Funcs   []FuncFanOut `json:"funcs"`
+}
+
+// fanOut counts the distinct call targets, such as fmt.Println or c.Do, of
+// every function.
+type fanOut struct {
+	funcs []FuncFanOut
+}
+
+func (m *fanOut) Name() string { return FanOutMetric }
+
+func (m *fanOut) Description() string {
+	return "Distinct functions each function calls. A high fan-out means a function depends on much and is affected by changes to all of it."
+}
+
+func (m *fanOut) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		targets := map[string]bool{}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := call.Fun.(*ast.Ident); ok && builtins[id.Name] {
+				return true
+			}
+			targets[types.ExprString(call.Fun)] = true
+			return true
+		})
+		m.funcs = append(m.funcs, FuncFanOut{Func: funcName(fn), FanOut: len(targets)})
+	}
+}
+
+// Result returns the FanOut of the package.
+func (m *fanOut) Result() interface{} {
+	r := FanOut{Funcs: append([]FuncFanOut{}, m.funcs...)}
+	if len(m.funcs) == 0 {
+		return r
+	}
+	total := 0
+	for _, f := range m.funcs {
+		total += f.FanOut
+		if f.FanOut > r.Highest.FanOut {
+			r.Highest = f
+		}
+	}
+	r.Average = float64(total) / float64(len(m.funcs))
+	return r
+}
+
+func (m *fanOut) Merge(results []interface{}) {
+	for _, r := range results {
+		m.funcs = append(m.funcs, r.(FanOut).Funcs...)
+	}
+}
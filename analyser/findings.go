@@ -0,0 +1,95 @@
+package analyser
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// HighComplexity is the cyclomatic complexity from which a function is
+// reported as a Finding, McCabe's own suggested limit.
+const HighComplexity = 10
+
+// Finding is a potential problem found by one of the heuristic checks, in a
+// form for tools to consume alongside the metrics.
+type Finding struct {
+	// Rule is the ID of one of Rules, or the check of a Warning reported
+	// by a metric registered elsewhere.
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	// Severity is warning or note.
+	Severity string `json:"severity"`
+}
+
+// Rule describes a kind of Finding.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    string
+}
+
+// Rules describes every kind of Finding the built-in checks report.
+var Rules = []Rule{
+	{ID: "shadow", Description: "A variable shadows one of an enclosing block that is used after it", Severity: "warning"},
+	{ID: "deferred-result", Description: "A named result is reassigned inside a deferred closure", Severity: "warning"},
+	{ID: "bare-receive", Description: "A channel receive outside a select, in a function with no select that can time out or be cancelled, may block forever", Severity: "warning"},
+	{ID: "ignored-error", Description: "The error returned by a call is assigned to _ or dropped", Severity: "warning"},
+	{ID: "stub", Description: "A function does nothing but panic or return zero values marked TODO", Severity: "warning"},
+	{ID: "complexity", Description: fmt.Sprintf("A function has a cyclomatic complexity of %d or more", HighComplexity), Severity: "warning"},
+	{ID: "large-value-param", Description: fmt.Sprintf("An exported function takes a struct of %d or more fields by value", LargeStructFields), Severity: "note"},
+	{ID: "mixed-receivers", Description: "A type has methods with both pointer and value receivers", Severity: "warning"},
+	{ID: "undocumented", Description: "An exported symbol has no doc comment", Severity: "note"},
+	{ID: "terse-doc", Description: "A doc comment does little more than restate the name", Severity: "note"},
+}
+
+// findings gathers the Findings of a result from its Warnings, overly
+// complex functions, types mixing receivers, large structs passed by value
+// and poorly documented exported symbols.
+func findings(r Result) []Finding {
+	severities := map[string]string{}
+	for _, rule := range Rules {
+		severities[rule.ID] = rule.Severity
+	}
+	finding := func(rule, message string, pos token.Position) Finding {
+		severity, ok := severities[rule]
+		if !ok {
+			severity = "warning"
+		}
+		return Finding{Rule: rule, Message: message, File: pos.Filename, Line: pos.Line, Column: pos.Column, Severity: severity}
+	}
+
+	out := []Finding{}
+	for _, w := range r.Warnings() {
+		out = append(out, finding(w.Check, w.Message, w.Pos))
+	}
+
+	complexity, _ := r.Metrics[ComplexityMetric].([]FuncComplexity)
+	for _, c := range complexity {
+		if c.Complexity >= HighComplexity {
+			out = append(out, finding("complexity", fmt.Sprintf("%s has a cyclomatic complexity of %d", c.Func, c.Complexity), c.Pos))
+		}
+	}
+
+	receivers, _ := r.Metrics[ReceiversMetric].([]ReceiverKinds)
+	for _, k := range receivers {
+		if k.Mixed() {
+			out = append(out, finding("mixed-receivers", fmt.Sprintf("%s has %d pointer and %d value receiver(s)", k.Type, k.Pointer, k.Value), k.Pos))
+		}
+	}
+
+	large, _ := r.Metrics[LargeParamsMetric].([]LargeParam)
+	for _, p := range large {
+		out = append(out, finding("large-value-param", p.String(), p.Pos))
+	}
+
+	docs, _ := r.Metrics[DocsMetric].(DocCoverage)
+	for _, s := range docs.Undocumented {
+		out = append(out, finding("undocumented", fmt.Sprintf("%s %s has no doc comment", s.Kind, s.Name), s.Pos))
+	}
+	for _, s := range docs.Terse {
+		out = append(out, finding("terse-doc", fmt.Sprintf("the doc comment of %s %s only restates its name", s.Kind, s.Name), s.Pos))
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// FuncLength is the number of lines a function spans.
+type FuncLength struct {
+	Func  string         `json:"func"`
+	Lines int            `json:"lines"`
+	Pos   token.Position `json:"position"`
+}
+
+// funcLengths measures every function with a body, from its func keyword to
+// its closing brace.
+type funcLengths struct {
+	funcs []FuncLength
+}
+
+func (m *funcLengths) Name() string { return FuncLinesMetric }
+
+func (m *funcLengths) Description() string {
+	return "Lines spanned by each function. Long functions tend to do several things and are worth splitting."
+}
+
+func (m *funcLengths) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil {
+			m.funcs = append(m.funcs, FuncLength{Func: funcName(fn), Lines: funcLines(fset, fn), Pos: fset.Position(fn.Pos())})
+		}
+	}
+}
+
+// Result returns the FuncLength of every function in source order.
+func (m *funcLengths) Result() interface{} {
+	return append([]FuncLength{}, m.funcs...)
+}
+
+func (m *funcLengths) Merge(results []interface{}) {
+	for _, r := range results {
+		m.funcs = append(m.funcs, r.([]FuncLength)...)
+	}
+}
+
+// funcLines returns the number of lines fn spans.
+func funcLines(fset *token.FileSet, fn *ast.FuncDecl) int {
+	return fset.Position(fn.End()).Line - fset.Position(fn.Pos()).Line + 1
+}
+
+// LongFuncs returns the functions spanning more than limit lines, longest
+// first.
+func LongFuncs(funcs []FuncLength, limit int) []FuncLength {
+	out := []FuncLength{}
+	for _, f := range funcs {
+		if f.Lines > limit {
+			out = append(out, f)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Lines > out[j].Lines })
+	return out
+}
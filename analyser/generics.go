@@ -0,0 +1,47 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// generics finds the exported functions and types declaring type
+// parameters. Methods can't declare their own, so only those of generic
+// types are generic, and they're counted with their type.
+type generics struct {
+	symbols []Symbol
+}
+
+func (m *generics) Name() string { return GenericsMetric }
+
+func (m *generics) Description() string {
+	return "Exported functions and types declaring type parameters."
+}
+
+func (m *generics) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.IsExported() && d.Type.TypeParams.NumFields() > 0 {
+				m.symbols = append(m.symbols, Symbol{Name: d.Name.Name, Kind: "func", Pos: fset.Position(d.Name.Pos())})
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				if ts, ok := s.(*ast.TypeSpec); ok && ts.Name.IsExported() && ts.TypeParams.NumFields() > 0 {
+					m.symbols = append(m.symbols, Symbol{Name: ts.Name.Name, Kind: "type", Pos: fset.Position(ts.Name.Pos())})
+				}
+			}
+		}
+	}
+}
+
+// Result returns the generic functions and types in source order.
+func (m *generics) Result() interface{} {
+	return append([]Symbol{}, m.symbols...)
+}
+
+func (m *generics) Merge(results []interface{}) {
+	for _, r := range results {
+		m.symbols = append(m.symbols, r.([]Symbol)...)
+	}
+}
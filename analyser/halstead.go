@@ -0,0 +1,131 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+)
+
+// Halstead holds Halstead's counts of the operators and operands in code,
+// along with the volume derived from them:
+//
+//	V = (N1 + N2) * log2(n1 + n2)
+//
+// where n1 and n2 are the distinct operators and operands and N1 and N2 their
+// total occurrences.
+type Halstead struct {
+	DistinctOperators int     `json:"distinctOperators"`
+	DistinctOperands  int     `json:"distinctOperands"`
+	Operators         int     `json:"operators"`
+	Operands          int     `json:"operands"`
+	Volume            float64 `json:"volume"`
+}
+
+// halsteadCounter tallies operators and operands.
+type halsteadCounter struct {
+	operators map[string]int
+	operands  map[string]int
+}
+
+func newHalsteadCounter() *halsteadCounter {
+	return &halsteadCounter{operators: map[string]int{}, operands: map[string]int{}}
+}
+
+// count adds the operators and operands found in n. Identifiers and literals
+// are operands, while operators, keywords and punctuation such as calls and
+// indexing are operators.
+func (h *halsteadCounter) count(n ast.Node) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			h.operands[n.Name]++
+		case *ast.BasicLit:
+			h.operands[n.Value]++
+		case *ast.BinaryExpr:
+			h.operators[n.Op.String()]++
+		case *ast.UnaryExpr:
+			h.operators[n.Op.String()]++
+		case *ast.AssignStmt:
+			h.operators[n.Tok.String()]++
+		case *ast.IncDecStmt:
+			h.operators[n.Tok.String()]++
+		case *ast.BranchStmt:
+			h.operators[n.Tok.String()]++
+		case *ast.SendStmt:
+			h.operators["<-"]++
+		case *ast.CallExpr:
+			h.operators["()"]++
+		case *ast.IndexExpr:
+			h.operators["[]"]++
+		case *ast.SliceExpr:
+			h.operators["[:]"]++
+		case *ast.SelectorExpr:
+			h.operators["."]++
+		case *ast.StarExpr:
+			h.operators["*"]++
+		case *ast.TypeAssertExpr:
+			h.operators[".()"]++
+		case *ast.KeyValueExpr:
+			h.operators[":"]++
+		case *ast.CompositeLit:
+			h.operators["{}"]++
+		case *ast.FuncLit:
+			h.operators["func"]++
+		case *ast.IfStmt:
+			h.operators["if"]++
+		case *ast.ForStmt:
+			h.operators["for"]++
+		case *ast.RangeStmt:
+			h.operators["range"]++
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			h.operators["switch"]++
+		case *ast.SelectStmt:
+			h.operators["select"]++
+		case *ast.CaseClause, *ast.CommClause:
+			h.operators["case"]++
+		case *ast.ReturnStmt:
+			h.operators["return"]++
+		case *ast.GoStmt:
+			h.operators["go"]++
+		case *ast.DeferStmt:
+			h.operators["defer"]++
+		}
+		return true
+	})
+}
+
+func (h *halsteadCounter) result() Halstead {
+	r := Halstead{DistinctOperators: len(h.operators), DistinctOperands: len(h.operands)}
+	for _, n := range h.operators {
+		r.Operators += n
+	}
+	for _, n := range h.operands {
+		r.Operands += n
+	}
+	if vocabulary := r.DistinctOperators + r.DistinctOperands; vocabulary > 0 {
+		r.Volume = float64(r.Operators+r.Operands) * math.Log2(float64(vocabulary))
+	}
+	return r
+}
+
+// halstead computes the Halstead counts of the function bodies of a package.
+type halstead struct {
+	c *halsteadCounter
+}
+
+func (m *halstead) Name() string { return HalsteadMetric }
+
+func (m *halstead) Description() string {
+	return "Halstead volume, the size of the code measured by its operators and operands. Used to derive the maintainability index."
+}
+
+func (m *halstead) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil {
+			m.c.count(fn.Body)
+		}
+	}
+}
+
+// Result returns the Halstead counts of the package.
+func (m *halstead) Result() interface{} { return m.c.result() }
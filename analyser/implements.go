@@ -0,0 +1,239 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Implementation is a concrete type whose method set satisfies an interface
+// declared in the same package.
+type Implementation struct {
+	Type      string `json:"type"`
+	Interface string `json:"interface"`
+	// Pointer is set when only a pointer to the type implements it.
+	Pointer bool `json:"pointer"`
+}
+
+// implementations matches the method sets of exported concrete types against
+// exported interfaces by name and signature. With only the AST, interfaces
+// embedding interfaces from other packages can't be resolved and are
+// skipped, and methods promoted from embedded fields aren't counted; given
+// type information, go/types decides exactly.
+type implementations struct {
+	// interfaces maps each interface to its methods, or nil if it can't be
+	// resolved, and embeds to the local interfaces it embeds.
+	interfaces map[string]map[string]string
+	embeds     map[string][]string
+	concrete   []string
+	// values and pointers are the method sets of T and *T.
+	values   map[string]map[string]string
+	pointers map[string]map[string]string
+	// typed is set by ProcessTypes and replaces what is found from the AST.
+	typed []Implementation
+}
+
+func newImplementations() *implementations {
+	return &implementations{
+		interfaces: map[string]map[string]string{},
+		embeds:     map[string][]string{},
+		values:     map[string]map[string]string{},
+		pointers:   map[string]map[string]string{},
+	}
+}
+
+func (m *implementations) Name() string { return ImplementationsMetric }
+
+func (m *implementations) Description() string {
+	return "Exported types satisfying the package's own exported interfaces. Interfaces nothing implements may be meant for callers to satisfy."
+}
+
+func (m *implementations) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				ts, ok := s.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				it, ok := ts.Type.(*ast.InterfaceType)
+				if !ok {
+					if ts.Name.IsExported() && ts.TypeParams == nil {
+						m.concrete = append(m.concrete, ts.Name.Name)
+					}
+					continue
+				}
+				m.addInterface(ts.Name.Name, it)
+			}
+		case *ast.FuncDecl:
+			recv := receiverName(d)
+			if recv == "" {
+				continue
+			}
+			sig := signatureKey(d.Type)
+			if m.pointers[recv] == nil {
+				m.pointers[recv] = map[string]string{}
+				m.values[recv] = map[string]string{}
+			}
+			m.pointers[recv][d.Name.Name] = sig
+			if _, isPtr := d.Recv.List[0].Type.(*ast.StarExpr); !isPtr {
+				m.values[recv][d.Name.Name] = sig
+			}
+		}
+	}
+}
+
+func (m *implementations) addInterface(name string, it *ast.InterfaceType) {
+	methods := map[string]string{}
+	for _, field := range it.Methods.List {
+		switch t := field.Type.(type) {
+		case *ast.FuncType:
+			for _, n := range field.Names {
+				methods[n.Name] = signatureKey(t)
+			}
+		case *ast.Ident:
+			m.embeds[name] = append(m.embeds[name], t.Name)
+		default:
+			// embedded from elsewhere, or a type constraint
+			methods = nil
+		}
+		if methods == nil {
+			break
+		}
+	}
+	m.interfaces[name] = methods
+}
+
+// methodSet resolves the methods of an interface, including those of the
+// local interfaces it embeds, or returns nil if it can't.
+func (m *implementations) methodSet(name string, seen map[string]bool) map[string]string {
+	own, ok := m.interfaces[name]
+	if !ok || own == nil || seen[name] {
+		return nil
+	}
+	seen[name] = true
+	out := map[string]string{}
+	for k, v := range own {
+		out[k] = v
+	}
+	for _, e := range m.embeds[name] {
+		embedded := m.methodSet(e, seen)
+		if embedded == nil {
+			return nil
+		}
+		for k, v := range embedded {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// ProcessTypes finds the implementations among the exported types of a type
+// checked package.
+func (m *implementations) ProcessTypes(pkg *types.Package, info *types.Info) {
+	interfaces, concrete := []*types.TypeName{}, []*types.TypeName{}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !tn.Exported() || tn.IsAlias() {
+			continue
+		}
+		if types.IsInterface(tn.Type()) {
+			interfaces = append(interfaces, tn)
+		} else {
+			concrete = append(concrete, tn)
+		}
+	}
+
+	m.typed = []Implementation{}
+	for _, i := range interfaces {
+		iface, ok := i.Type().Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 || !iface.IsMethodSet() {
+			// empty, or a type constraint
+			continue
+		}
+		for _, t := range concrete {
+			if named, ok := t.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+				continue
+			}
+			switch {
+			case types.Implements(t.Type(), iface):
+				m.typed = append(m.typed, Implementation{Type: t.Name(), Interface: i.Name()})
+			case types.Implements(types.NewPointer(t.Type()), iface):
+				m.typed = append(m.typed, Implementation{Type: t.Name(), Interface: i.Name(), Pointer: true})
+			}
+		}
+	}
+}
+
+// Result returns the implementations sorted by interface then type.
+func (m *implementations) Result() interface{} {
+	if m.typed != nil {
+		sortImplementations(m.typed)
+		return append([]Implementation{}, m.typed...)
+	}
+	out := []Implementation{}
+	for name := range m.interfaces {
+		if !ast.IsExported(name) {
+			continue
+		}
+		methods := m.methodSet(name, map[string]bool{})
+		if len(methods) == 0 {
+			// nothing to learn from types satisfying an empty interface
+			continue
+		}
+		for _, t := range m.concrete {
+			switch {
+			case satisfies(m.values[t], methods):
+				out = append(out, Implementation{Type: t, Interface: name})
+			case satisfies(m.pointers[t], methods):
+				out = append(out, Implementation{Type: t, Interface: name, Pointer: true})
+			}
+		}
+	}
+	sortImplementations(out)
+	return out
+}
+
+func sortImplementations(out []Implementation) {
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Interface != out[j].Interface {
+			return out[i].Interface < out[j].Interface
+		}
+		return out[i].Type < out[j].Type
+	})
+}
+
+func satisfies(set, methods map[string]string) bool {
+	for name, sig := range methods {
+		if set[name] != sig {
+			return false
+		}
+	}
+	return true
+}
+
+// signatureKey renders the parameter and result types of a function,
+// leaving out their names so that they can be compared.
+func signatureKey(ft *ast.FuncType) string {
+	list := func(fl *ast.FieldList) string {
+		if fl == nil {
+			return ""
+		}
+		parts := []string{}
+		for _, field := range fl.List {
+			n := len(field.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				parts = append(parts, types.ExprString(field.Type))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return "(" + list(ft.Params) + ") (" + list(ft.Results) + ")"
+}
@@ -0,0 +1,111 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// imports collects the import paths used across a package.
+type imports struct {
+	seen map[string]bool
+}
+
+func (m *imports) Name() string { return ImportsMetric }
+
+func (m *imports) Description() string {
+	return "Packages imported. Many external imports widen the dependency tree callers inherit."
+}
+
+func (m *imports) Process(fset *token.FileSet, f *ast.File) {
+	for _, i := range f.Imports {
+		m.seen[importPath(i)] = true
+	}
+}
+
+// Result returns the sorted import paths.
+func (m *imports) Result() interface{} {
+	out := []string{}
+	for i := range m.seen {
+		out = append(out, i)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (m *imports) Merge(results []interface{}) {
+	for _, r := range results {
+		for _, i := range r.([]string) {
+			m.seen[i] = true
+		}
+	}
+}
+
+// FileImports is the number of packages a file imports.
+type FileImports struct {
+	File    string `json:"file"`
+	Imports int    `json:"imports"`
+}
+
+// importsPerFile counts the imports of every file in a package.
+type importsPerFile struct {
+	files []FileImports
+}
+
+func (m *importsPerFile) Name() string { return ImportsPerFileMetric }
+
+func (m *importsPerFile) Description() string {
+	return "Imports of each file. A file importing far more than the rest is often doing too much."
+}
+
+func (m *importsPerFile) Process(fset *token.FileSet, f *ast.File) {
+	m.files = append(m.files, FileImports{File: fset.File(f.Pos()).Name(), Imports: len(f.Imports)})
+}
+
+// Result returns the FileImports of every file in the order processed.
+func (m *importsPerFile) Result() interface{} {
+	return append([]FileImports{}, m.files...)
+}
+
+func (m *importsPerFile) Merge(results []interface{}) {
+	for _, r := range results {
+		m.files = append(m.files, r.([]FileImports)...)
+	}
+}
+
+// importPath returns the unquoted path of an import.
+func importPath(i *ast.ImportSpec) string {
+	p, err := strconv.Unquote(i.Path.Value)
+	if err != nil {
+		return i.Path.Value
+	}
+	return p
+}
+
+// IsStdlib reports whether an import path belongs to the standard library,
+// which, unlike every other module, has no dot in its first path element.
+func IsStdlib(path string) bool {
+	return !strings.Contains(ImportDomain(path), ".")
+}
+
+// ImportDomain returns the first element of an import path, which for
+// anything outside the standard library is the host it is served from.
+func ImportDomain(path string) string {
+	if i := strings.Index(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// ImportDomains counts the imports outside the standard library by domain.
+func ImportDomains(imports []string) map[string]int {
+	out := map[string]int{}
+	for _, i := range imports {
+		if !IsStdlib(i) {
+			out[ImportDomain(i)]++
+		}
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package analyser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// LargeStructFields is the number of fields from which a struct passed by
+// value is reported. Without the sizes of types the number of fields stands
+// in for how much is copied on each call.
+const LargeStructFields = 8
+
+// LargeParam is a parameter of an exported function passing a struct with
+// many fields by value.
+type LargeParam struct {
+	Func string `json:"func"`
+	// Param is the parameter's name, empty if it has none.
+	Param  string         `json:"param"`
+	Type   string         `json:"type"`
+	Fields int            `json:"fields"`
+	Pos    token.Position `json:"position"`
+}
+
+// valueParam is a parameter of an exported function whose type is named in
+// the package and isn't a pointer.
+type valueParam struct {
+	fn, param, typ string
+	pos            token.Position
+}
+
+// largeParams flags exported functions taking a struct of the package with
+// many fields by value, copying it on every call. Passing a pointer avoids
+// the copy.
+type largeParams struct {
+	params []valueParam
+	// fields counts the fields of each struct type of the package, an
+	// embedded field counting as one.
+	fields map[string]int
+}
+
+func newLargeParams() *largeParams {
+	return &largeParams{fields: map[string]int{}}
+}
+
+func (m *largeParams) Name() string { return LargeParamsMetric }
+
+func (m *largeParams) Description() string {
+	return fmt.Sprintf("Exported functions taking a struct of %d or more fields by value, copying it on every call. A pointer may perform better.", LargeStructFields)
+}
+
+func (m *largeParams) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				ts, ok := s.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					m.fields[ts.Name.Name] = fieldCount(st.Fields)
+				}
+			}
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			for _, p := range d.Type.Params.List {
+				id, ok := p.Type.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if len(p.Names) == 0 {
+					m.params = append(m.params, valueParam{fn: funcName(d), typ: id.Name, pos: fset.Position(p.Pos())})
+				}
+				for _, name := range p.Names {
+					m.params = append(m.params, valueParam{fn: funcName(d), param: name.Name, typ: id.Name, pos: fset.Position(name.Pos())})
+				}
+			}
+		}
+	}
+}
+
+// fieldCount returns the number of fields of a struct, counting each name of
+// a field declaring several.
+func fieldCount(fields *ast.FieldList) int {
+	n := 0
+	for _, f := range fields.List {
+		if len(f.Names) == 0 {
+			n++
+		}
+		n += len(f.Names)
+	}
+	return n
+}
+
+// Result returns the LargeParam of every parameter passing a struct of
+// LargeStructFields or more fields by value, in source order. They aren't
+// Warnings, being a hint at performance rather than a likely bug.
+func (m *largeParams) Result() interface{} {
+	out := []LargeParam{}
+	for _, p := range m.params {
+		if fields := m.fields[p.typ]; fields >= LargeStructFields {
+			out = append(out, LargeParam{Func: p.fn, Param: p.param, Type: p.typ, Fields: fields, Pos: p.pos})
+		}
+	}
+	return out
+}
+
+// String describes the parameter as the function taking the struct.
+func (p LargeParam) String() string {
+	param := p.Type
+	if p.Param != "" {
+		param = p.Param + " " + p.Type
+	}
+	return fmt.Sprintf("%s takes %s by value, a struct of %d fields", p.Func, param, p.Fields)
+}
@@ -0,0 +1,90 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+	"strings"
+)
+
+// FileLines is the number of lines in a file.
+type FileLines struct {
+	File  string `json:"file"`
+	Lines int    `json:"lines"`
+}
+
+// LineStats summarises the length of the files in a package.
+type LineStats struct {
+	Files  []FileLines `json:"files"`
+	Mean   float64     `json:"mean"`
+	StdDev float64     `json:"stdDev"`
+}
+
+// Outliers returns the files longer than factor times the mean.
+func (s LineStats) Outliers(factor float64) []FileLines {
+	out := []FileLines{}
+	for _, fl := range s.Files {
+		if float64(fl.Lines) > factor*s.Mean {
+			out = append(out, fl)
+		}
+	}
+	return out
+}
+
+// TestLines sums the lines of the _test.go files and of every other file.
+func (s LineStats) TestLines() (test, code int) {
+	for _, fl := range s.Files {
+		if strings.HasSuffix(fl.File, "_test.go") {
+			test += fl.Lines
+		} else {
+			code += fl.Lines
+		}
+	}
+	return test, code
+}
+
+// fileLines counts the lines of every file in a package.
+type fileLines struct {
+	files []FileLines
+}
+
+func (m *fileLines) Name() string { return FileLinesMetric }
+
+func (m *fileLines) Description() string {
+	return "Lines per file, and the ratio of test to code. Files far longer than the mean are candidates for splitting."
+}
+
+func (m *fileLines) Process(fset *token.FileSet, f *ast.File) {
+	tf := fset.File(f.Pos())
+	m.files = append(m.files, FileLines{File: tf.Name(), Lines: tf.LineCount()})
+}
+
+// Result returns the LineStats of the files processed.
+func (m *fileLines) Result() interface{} {
+	s := LineStats{Files: []FileLines{}}
+	if len(m.files) == 0 {
+		return s
+	}
+	s.Files = m.files
+
+	total := 0
+	for _, fl := range s.Files {
+		total += fl.Lines
+	}
+	s.Mean = float64(total) / float64(len(s.Files))
+
+	variance := 0.
+	for _, fl := range s.Files {
+		d := float64(fl.Lines) - s.Mean
+		variance += d * d
+	}
+	s.StdDev = math.Sqrt(variance / float64(len(s.Files)))
+
+	return s
+}
+
+func (m *fileLines) Merge(results []interface{}) {
+	for _, r := range results {
+		m.files = append(m.files, r.(LineStats).Files...)
+	}
+}
@@ -0,0 +1,77 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+)
+
+// Maintainability is the Maintainability Index of a package. The index of
+// each function is computed with the normalised formula used by Visual
+// Studio,
+//
+//	MI = max(0, (171 - 5.2*ln(V) - 0.23*G - 16.2*ln(LOC)) * 100 / 171)
+//
+// where V is the Halstead volume, G the cyclomatic complexity and LOC the
+// lines of the function. The index of the package is the mean over its
+// functions, from 0 (unmaintainable) to 100.
+type Maintainability struct {
+	Index float64 `json:"index"`
+	Label string  `json:"label"`
+	Funcs int     `json:"funcs"`
+}
+
+// maintainabilityLabel describes an index using Visual Studio's thresholds.
+func maintainabilityLabel(index float64) string {
+	switch {
+	case index >= 20:
+		return "good"
+	case index >= 10:
+		return "moderate"
+	}
+	return "poor"
+}
+
+// funcMaintainability returns the Maintainability Index of a single function.
+func funcMaintainability(fset *token.FileSet, fn *ast.FuncDecl) float64 {
+	hc := newHalsteadCounter()
+	hc.count(fn.Body)
+	v := math.Max(hc.result().Volume, 1)
+	g := float64(cyclomatic(fn.Body))
+	loc := float64(funcLines(fset, fn))
+
+	mi := (171 - 5.2*math.Log(v) - 0.23*g - 16.2*math.Log(loc)) * 100 / 171
+	return math.Max(0, mi)
+}
+
+// maintainability computes the Maintainability Index of a package.
+type maintainability struct {
+	total float64
+	funcs int
+}
+
+func (m *maintainability) Name() string { return MaintainabilityMetric }
+
+func (m *maintainability) Description() string {
+	return "Maintainability index from 0 to 100, combining volume, complexity and length. 20 and above is good, 10 to 20 moderate and below 10 poor."
+}
+
+func (m *maintainability) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil {
+			m.total += funcMaintainability(fset, fn)
+			m.funcs++
+		}
+	}
+}
+
+// Result returns the Maintainability of the package. A package without
+// functions is trivially maintainable.
+func (m *maintainability) Result() interface{} {
+	r := Maintainability{Index: 100, Funcs: m.funcs}
+	if m.funcs > 0 {
+		r.Index = m.total / float64(m.funcs)
+	}
+	r.Label = maintainabilityLabel(r.Index)
+	return r
+}
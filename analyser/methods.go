@@ -0,0 +1,58 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// MethodName is a method name along with the types it is declared on.
+type MethodName struct {
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
+}
+
+// methodNames groups the methods of a package by name. A name declared on
+// many types hints at an interface the types implicitly share.
+type methodNames struct {
+	types map[string][]string
+}
+
+func newMethodNames() *methodNames {
+	return &methodNames{types: map[string][]string{}}
+}
+
+func (m *methodNames) Name() string { return MethodNamesMetric }
+
+func (m *methodNames) Description() string {
+	return "Method names and the types declaring them. A name shared by many types hints at an interface they implicitly share."
+}
+
+func (m *methodNames) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		if recv := receiverName(fn); recv != "" {
+			m.types[fn.Name.Name] = append(m.types[fn.Name.Name], recv)
+		}
+	}
+}
+
+// Result returns every method name, those declared on the most types first.
+func (m *methodNames) Result() interface{} {
+	out := []MethodName{}
+	for name, types := range m.types {
+		types = append([]string{}, types...)
+		sort.Strings(types)
+		out = append(out, MethodName{Name: name, Types: types})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Types) != len(out[j].Types) {
+			return len(out[i].Types) > len(out[j].Types)
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,113 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// MutableSite is an exported function or struct field exposing a map or a
+// slice of pointers.
+type MutableSite struct {
+	// Name is the function, or the type and field as Type.Field.
+	Name string `json:"name"`
+	// Maps and PointerSlices say which of the two are exposed.
+	Maps          bool           `json:"maps"`
+	PointerSlices bool           `json:"pointerSlices"`
+	Pos           token.Position `json:"position"`
+}
+
+// mutableSignatures finds exported functions whose parameters or results,
+// and exported struct fields whose types, involve a map or a slice of
+// pointers. Both share their contents with the caller, and maps may be nil,
+// which is easy to trip over.
+type mutableSignatures struct {
+	sites []MutableSite
+}
+
+func (m *mutableSignatures) Name() string { return MutableMetric }
+
+func (m *mutableSignatures) Description() string {
+	return "Exported signatures and fields exposing maps or slices of pointers, which share their contents with callers."
+}
+
+func (m *mutableSignatures) Process(fset *token.FileSet, f *ast.File) {
+	add := func(name string, pos token.Pos, exprs ...ast.Expr) {
+		site := MutableSite{Name: name, Pos: fset.Position(pos)}
+		for _, e := range exprs {
+			maps, ptrSlices := mutableTypes(e)
+			site.Maps = site.Maps || maps
+			site.PointerSlices = site.PointerSlices || ptrSlices
+		}
+		if site.Maps || site.PointerSlices {
+			m.sites = append(m.sites, site)
+		}
+	}
+
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Name.IsExported() {
+				add(funcName(d), d.Name.Pos(), fieldTypes(d.Type.Params, d.Type.Results)...)
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				ts, ok := s.(*ast.TypeSpec)
+				if !ok || !ts.Name.IsExported() {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, name := range field.Names {
+						if name.IsExported() {
+							add(ts.Name.Name+"."+name.Name, name.Pos(), field.Type)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Result returns the sites in source order.
+func (m *mutableSignatures) Result() interface{} {
+	return append([]MutableSite{}, m.sites...)
+}
+
+func (m *mutableSignatures) Merge(results []interface{}) {
+	for _, r := range results {
+		m.sites = append(m.sites, r.([]MutableSite)...)
+	}
+}
+
+func fieldTypes(lists ...*ast.FieldList) []ast.Expr {
+	out := []ast.Expr{}
+	for _, l := range lists {
+		if l == nil {
+			continue
+		}
+		for _, field := range l.List {
+			out = append(out, field.Type)
+		}
+	}
+	return out
+}
+
+// mutableTypes reports whether a type expression mentions a map or a slice
+// of pointers anywhere within it.
+func mutableTypes(expr ast.Expr) (maps, ptrSlices bool) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.MapType:
+			maps = true
+		case *ast.ArrayType:
+			if _, isPtr := n.Elt.(*ast.StarExpr); isPtr && n.Len == nil {
+				ptrSlices = true
+			}
+		}
+		return true
+	})
+	return maps, ptrSlices
+}
@@ -0,0 +1,139 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// OptionsFunc is an exported function following the functional options
+// idiom: its last parameter is variadic of an option func type declared in
+// the package, such as ...Option where type Option func(*Server).
+type OptionsFunc struct {
+	Func   string `json:"func"`
+	Option string `json:"option"`
+	// Type is the type the options configure, taken from the parameter of
+	// the option type or else the result of the function.
+	Type string         `json:"type"`
+	Pos  token.Position `json:"position"`
+}
+
+// functionalOptions matches variadic parameters against the func types
+// declared across a package, which needn't be in the same file.
+type functionalOptions struct {
+	// optionTypes maps each declared func type to the type it takes.
+	optionTypes map[string]string
+	candidates  []OptionsFunc
+}
+
+func newFunctionalOptions() *functionalOptions {
+	return &functionalOptions{optionTypes: map[string]string{}}
+}
+
+func (m *functionalOptions) Name() string { return OptionsMetric }
+
+func (m *functionalOptions) Description() string {
+	return "Exported functions configured through functional options, and the option types they take."
+}
+
+func (m *functionalOptions) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				ts, ok := s.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				if ft, ok := ts.Type.(*ast.FuncType); ok {
+					if configures, ok := optionFuncType(ft); ok {
+						m.optionTypes[ts.Name.Name] = configures
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() || d.Type.Params.NumFields() == 0 {
+				continue
+			}
+			params := d.Type.Params.List
+			ellipsis, ok := params[len(params)-1].Type.(*ast.Ellipsis)
+			if !ok {
+				continue
+			}
+			elem, ok := ellipsis.Elt.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			built := ""
+			if d.Type.Results.NumFields() > 0 {
+				built = namedType(d.Type.Results.List[0].Type)
+			}
+			m.candidates = append(m.candidates, OptionsFunc{
+				Func:   funcName(d),
+				Option: elem.Name,
+				Type:   built,
+				Pos:    fset.Position(d.Name.Pos()),
+			})
+		}
+	}
+}
+
+// Result returns the functions taking functional options in source order.
+func (m *functionalOptions) Result() interface{} {
+	out := []OptionsFunc{}
+	for _, c := range m.candidates {
+		configures, ok := m.optionTypes[c.Option]
+		if !ok {
+			continue
+		}
+		if configures != "" {
+			c.Type = configures
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// optionFuncType reports whether a func type looks like an option: it takes
+// nothing or a pointer to the type it configures, which is returned, and
+// returns nothing or an error. Func types such as middleware, taking and
+// returning values, aren't options.
+func optionFuncType(ft *ast.FuncType) (string, bool) {
+	switch ft.Results.NumFields() {
+	case 0:
+	case 1:
+		if id, ok := ft.Results.List[0].Type.(*ast.Ident); !ok || id.Name != "error" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	switch ft.Params.NumFields() {
+	case 0:
+		return "", true
+	case 1:
+		star, ok := ft.Params.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			return "", false
+		}
+		configures := namedType(star)
+		return configures, configures != ""
+	}
+	return "", false
+}
+
+// OptionTypes returns the distinct types configured through functional
+// options, sorted.
+func OptionTypes(funcs []OptionsFunc) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, f := range funcs {
+		if f.Type != "" && !seen[f.Type] {
+			seen[f.Type] = true
+			out = append(out, f.Type)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,72 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// knownOS and knownArch mirror the GOOS and GOARCH values go/build recognises
+// in filename suffixes.
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+	"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+	"windows": true, "zos": true,
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+	"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+	"sparc": true, "sparc64": true, "wasm": true,
+}
+
+// filePlatform returns the GOOS, GOARCH or GOOS/GOARCH pair a file is
+// restricted to by its name, or "" if the name carries no such suffix.
+func filePlatform(filename string) string {
+	name := strings.TrimSuffix(filepath.Base(filename), ".go")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return ""
+	}
+	l := strings.Split(strings.TrimSuffix(name[i:], "_test"), "_")
+	n := len(l)
+	if n >= 2 && knownOS[l[n-2]] && knownArch[l[n-1]] {
+		return l[n-2] + "/" + l[n-1]
+	}
+	if n >= 1 && (knownOS[l[n-1]] || knownArch[l[n-1]]) {
+		return l[n-1]
+	}
+	return ""
+}
+
+// platforms counts the files restricted to each platform by filename.
+type platforms struct {
+	counts map[string]int
+}
+
+func (m *platforms) Name() string { return PlatformsMetric }
+
+func (m *platforms) Description() string {
+	return "Files restricted to a platform by their name, such as _linux.go. Code that only builds on some platforms is easy to break unnoticed."
+}
+
+func (m *platforms) Process(fset *token.FileSet, f *ast.File) {
+	if p := filePlatform(fset.File(f.Pos()).Name()); p != "" {
+		m.counts[p]++
+	}
+}
+
+// Result returns the number of files for each GOOS, GOARCH or GOOS/GOARCH.
+func (m *platforms) Result() interface{} { return m.counts }
+
+func (m *platforms) Merge(results []interface{}) {
+	for _, r := range results {
+		for k, n := range r.(map[string]int) {
+			m.counts[k] += n
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// bareReceives flags channel receives outside of a select, in functions with
+// no select that could time out or be cancelled. Such receives block forever
+// if nothing is ever sent, leaking the goroutine.
+type bareReceives struct {
+	warnings []Warning
+}
+
+func (m *bareReceives) Name() string { return BareReceivesMetric }
+
+func (m *bareReceives) Description() string {
+	return "Channel receives outside any select that could time out. They block forever if nothing is sent, leaking the goroutine."
+}
+
+func (m *bareReceives) Process(fset *token.FileSet, f *ast.File) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body == nil || hasGuardedSelect(body) {
+			return true
+		}
+
+		comms := map[ast.Node]bool{}
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				// checked on its own
+				return false
+			case *ast.CommClause:
+				if n.Comm != nil {
+					ast.Inspect(n.Comm, func(c ast.Node) bool {
+						comms[c] = true
+						return true
+					})
+				}
+			case *ast.UnaryExpr:
+				if n.Op == token.ARROW && !comms[n] {
+					m.warnings = append(m.warnings, Warning{
+						Check:   "bare-receive",
+						Pos:     fset.Position(n.Pos()),
+						Message: "channel receive outside a select with a timeout or cancellation",
+					})
+				}
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// Result returns a Warning for every bare receive.
+func (m *bareReceives) Result() interface{} {
+	return append([]Warning{}, m.warnings...)
+}
+
+func (m *bareReceives) Merge(results []interface{}) {
+	for _, r := range results {
+		m.warnings = append(m.warnings, r.([]Warning)...)
+	}
+}
+
+// hasGuardedSelect reports whether body, outside of any function literals,
+// holds a select with a default case or a case receiving from time.After,
+// time.Tick, a timer or ctx.Done().
+func hasGuardedSelect(body *ast.BlockStmt) bool {
+	guarded := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		sel, ok := n.(*ast.SelectStmt)
+		if !ok {
+			return !guarded
+		}
+		for _, s := range sel.Body.List {
+			cc := s.(*ast.CommClause)
+			if cc.Comm == nil {
+				guarded = true
+			}
+			ast.Inspect(cc.Comm, func(n ast.Node) bool {
+				u, ok := n.(*ast.UnaryExpr)
+				if ok && u.Op == token.ARROW && isTimeoutOrDone(u.X) {
+					guarded = true
+				}
+				return !guarded
+			})
+		}
+		return !guarded
+	})
+	return guarded
+}
+
+// isTimeoutOrDone reports whether a received-from expression looks like
+// time.After(d), time.Tick(d), t.C or ctx.Done().
+func isTimeoutOrDone(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		switch sel.Sel.Name {
+		case "After", "Tick", "Done":
+			return true
+		}
+	case *ast.SelectorExpr:
+		return e.Sel.Name == "C"
+	}
+	return false
+}
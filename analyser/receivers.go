@@ -0,0 +1,80 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// ReceiverKinds counts the methods of a type by the kind of their receiver.
+type ReceiverKinds struct {
+	Type    string `json:"type"`
+	Pointer int    `json:"pointer"`
+	Value   int    `json:"value"`
+	// Pos is where the type is declared, or its first method if the type
+	// isn't declared in the package.
+	Pos token.Position `json:"position"`
+}
+
+// Mixed reports whether the type has both pointer and value receivers.
+func (k ReceiverKinds) Mixed() bool { return k.Pointer > 0 && k.Value > 0 }
+
+// receivers counts the pointer and value receivers of the methods of each
+// type. Mixing both on one type is usually an oversight: the method set of
+// the value then differs from that of the pointer.
+type receivers struct {
+	kinds map[string]*ReceiverKinds
+	decls map[string]token.Position
+}
+
+func newReceivers() *receivers {
+	return &receivers{kinds: map[string]*ReceiverKinds{}, decls: map[string]token.Position{}}
+}
+
+func (m *receivers) Name() string { return ReceiversMetric }
+
+func (m *receivers) Description() string {
+	return "Pointer and value receivers of each type's methods. A type mixing both has a value method set that differs from its pointer's, usually an oversight."
+}
+
+func (m *receivers) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				if ts, ok := s.(*ast.TypeSpec); ok {
+					m.decls[ts.Name.Name] = fset.Position(ts.Name.Pos())
+				}
+			}
+		case *ast.FuncDecl:
+			recv := receiverName(d)
+			if recv == "" {
+				continue
+			}
+			k, ok := m.kinds[recv]
+			if !ok {
+				k = &ReceiverKinds{Type: recv, Pos: fset.Position(d.Name.Pos())}
+				m.kinds[recv] = k
+			}
+			if _, isPtr := d.Recv.List[0].Type.(*ast.StarExpr); isPtr {
+				k.Pointer++
+			} else {
+				k.Value++
+			}
+		}
+	}
+}
+
+// Result returns the ReceiverKinds of every type with methods, sorted by
+// type.
+func (m *receivers) Result() interface{} {
+	out := []ReceiverKinds{}
+	for name, k := range m.kinds {
+		if pos, ok := m.decls[name]; ok {
+			k.Pos = pos
+		}
+		out = append(out, *k)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
@@ -0,0 +1,49 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// MultipleReturns counts the exported functions and methods returning more
+// than one value.
+type MultipleReturns struct {
+	Total int `json:"total"`
+	// EndingInError are those whose last result is an error, the (T, error)
+	// idiom.
+	EndingInError int `json:"endingInError"`
+}
+
+type multipleReturns struct {
+	r MultipleReturns
+}
+
+func (m *multipleReturns) Name() string { return MultipleReturnsMetric }
+
+func (m *multipleReturns) Description() string {
+	return "Exported functions returning more than one value, and how many end in an error, the usual (T, error) form."
+}
+
+func (m *multipleReturns) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || fn.Type.Results.NumFields() < 2 {
+			continue
+		}
+		m.r.Total++
+		results := fn.Type.Results.List
+		if id, ok := results[len(results)-1].Type.(*ast.Ident); ok && id.Name == "error" {
+			m.r.EndingInError++
+		}
+	}
+}
+
+func (m *multipleReturns) Result() interface{} { return m.r }
+
+func (m *multipleReturns) Merge(results []interface{}) {
+	for _, r := range results {
+		c := r.(MultipleReturns)
+		m.r.Total += c.Total
+		m.r.EndingInError += c.EndingInError
+	}
+}
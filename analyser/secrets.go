@@ -0,0 +1,153 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Secret is a string literal that looks like a hardcoded credential. The
+// literal itself is left out so that results don't spread it further.
+type Secret struct {
+	// Kind is the pattern matched, such as aws-access-key, or high-entropy
+	// for a literal only suspicious for its randomness.
+	Kind string `json:"kind"`
+	// Entropy is the Shannon entropy of the literal in bits per character.
+	Entropy float64        `json:"entropy"`
+	Length  int            `json:"length"`
+	Pos     token.Position `json:"position"`
+}
+
+// minSecretLength is the length from which a literal without whitespace is
+// considered for its entropy; shorter ones are rarely keys and often score
+// highly by chance.
+const minSecretLength = 20
+
+// MinSecretEntropy is the lowest entropy of a literal recorded as a
+// high-entropy Secret, so the lowest threshold SuspectedSecrets can apply.
+const MinSecretEntropy = 3.5
+
+// secretPatterns are the formats of well known credentials.
+var secretPatterns = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{"aws-access-key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}`)},
+	{"private-key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+}
+
+// secrets looks for string literals matching secretPatterns or long enough
+// and random enough to be keys. Import paths and struct tags are skipped.
+type secrets struct {
+	found []Secret
+}
+
+func (m *secrets) Name() string { return SecretsMetric }
+
+func (m *secrets) Description() string {
+	return "String literals that look like hardcoded credentials. Check each one; real secrets belong in configuration, not source."
+}
+
+func (m *secrets) Process(fset *token.FileSet, f *ast.File) {
+	tags := map[*ast.BasicLit]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.Field:
+			if n.Tag != nil {
+				tags[n.Tag] = true
+			}
+		case *ast.BasicLit:
+			if !tags[n] {
+				m.check(fset, n)
+			}
+		}
+		return true
+	})
+}
+
+func (m *secrets) check(fset *token.FileSet, lit *ast.BasicLit) {
+	if lit.Kind != token.STRING {
+		return
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	secret := Secret{Entropy: entropy(s), Length: len(s), Pos: fset.Position(lit.Pos())}
+	for _, p := range secretPatterns {
+		if p.re.MatchString(s) {
+			secret.Kind = p.kind
+			m.found = append(m.found, secret)
+			return
+		}
+	}
+	if len(s) >= minSecretLength && secret.Entropy >= MinSecretEntropy && strings.IndexFunc(s, unicode.IsSpace) < 0 {
+		secret.Kind = "high-entropy"
+		m.found = append(m.found, secret)
+	}
+}
+
+// Result returns the Secrets in source order.
+func (m *secrets) Result() interface{} {
+	return append([]Secret{}, m.found...)
+}
+
+func (m *secrets) Merge(results []interface{}) {
+	for _, r := range results {
+		m.found = append(m.found, r.([]Secret)...)
+	}
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	// summed in a fixed order so the result doesn't vary between runs
+	runes := []rune{}
+	for r := range counts {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	var h float64
+	for _, r := range runes {
+		p := float64(counts[r]) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// SuspectedSecrets returns the secrets matching a known pattern along with
+// the high-entropy ones whose entropy is at least threshold. Pattern matches
+// come first in source order, then the rest most random first.
+func SuspectedSecrets(found []Secret, threshold float64) []Secret {
+	out := []Secret{}
+	for _, s := range found {
+		if s.Kind != "high-entropy" || s.Entropy >= threshold {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		pi, pj := out[i].Kind != "high-entropy", out[j].Kind != "high-entropy"
+		if pi || pj {
+			return pi && !pj
+		}
+		return out[i].Entropy > out[j].Entropy
+	})
+	return out
+}
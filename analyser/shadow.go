@@ -0,0 +1,242 @@
+package analyser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// Warning is a potential problem found at a specific place in the source.
+type Warning struct {
+	Check   string         `json:"check"`
+	Pos     token.Position `json:"position"`
+	Message string         `json:"message"`
+}
+
+// String formats the Warning as position: message (check).
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s (%s)", w.Pos, w.Message, w.Check)
+}
+
+// scopeFrame is pushed for every node visited while walking a function. Only
+// nodes that open a new block carry declarations.
+type scopeFrame struct {
+	node  ast.Node
+	decls map[string]*ast.Ident
+}
+
+// shadow reports variables declared in a nested block that shadow a variable
+// of an enclosing block which is still used once the nested block ends, as
+// well as named results reassigned inside deferred closures.
+type shadow struct {
+	warnings []Warning
+}
+
+func (m *shadow) Name() string { return ShadowMetric }
+
+func (m *shadow) Description() string {
+	return "Variables shadowing one of an enclosing block that is used afterwards, often a mistake such as an err that is never checked."
+}
+
+func (m *shadow) Process(fset *token.FileSet, f *ast.File) {
+	uses := make(map[*ast.Object][]token.Pos)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Obj != nil && id.Obj.Decl != nil {
+			uses[id.Obj] = append(uses[id.Obj], id.Pos())
+		}
+		return true
+	})
+
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		m.warnings = append(m.warnings, shadowedIn(fset, fn, uses)...)
+		m.warnings = append(m.warnings, deferredResultAssigns(fset, fn)...)
+	}
+}
+
+// Result returns the Warnings found.
+func (m *shadow) Result() interface{} {
+	return append([]Warning{}, m.warnings...)
+}
+
+func (m *shadow) Merge(results []interface{}) {
+	for _, r := range results {
+		m.warnings = append(m.warnings, r.([]Warning)...)
+	}
+}
+
+func shadowedIn(fset *token.FileSet, fn *ast.FuncDecl, uses map[*ast.Object][]token.Pos) []Warning {
+	out := []Warning{}
+	stack := []*scopeFrame{}
+	bodies := map[*ast.BlockStmt]bool{}
+
+	declare := func(id *ast.Ident) {
+		if id == nil || id.Name == "_" {
+			return
+		}
+		cur := -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].decls != nil {
+				cur = i
+				break
+			}
+		}
+		if cur < 0 {
+			return
+		}
+		if _, redeclared := stack[cur].decls[id.Name]; redeclared {
+			return
+		}
+		for i := cur - 1; i >= 0; i-- {
+			outer, ok := stack[i].decls[id.Name]
+			if !ok {
+				continue
+			}
+			end := stack[cur].node.End()
+			for _, p := range uses[outer.Obj] {
+				if p > end {
+					out = append(out, Warning{
+						Check:   "shadow",
+						Pos:     fset.Position(id.Pos()),
+						Message: fmt.Sprintf("declaration of %q shadows declaration at line %d", id.Name, fset.Position(outer.Pos()).Line),
+					})
+					break
+				}
+			}
+			break
+		}
+		stack[cur].decls[id.Name] = id
+	}
+	declareFields := func(fl *ast.FieldList) {
+		if fl == nil {
+			return
+		}
+		for _, field := range fl.List {
+			for _, name := range field.Names {
+				declare(name)
+			}
+		}
+	}
+
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		frame := &scopeFrame{node: n}
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			frame.decls = map[string]*ast.Ident{}
+			stack = append(stack, frame)
+			declareFields(n.Recv)
+			declareFields(n.Type.Params)
+			declareFields(n.Type.Results)
+			bodies[n.Body] = true
+			return true
+		case *ast.FuncLit:
+			frame.decls = map[string]*ast.Ident{}
+			stack = append(stack, frame)
+			declareFields(n.Type.Params)
+			declareFields(n.Type.Results)
+			bodies[n.Body] = true
+			return true
+		case *ast.BlockStmt:
+			if !bodies[n] {
+				frame.decls = map[string]*ast.Ident{}
+			}
+		case *ast.IfStmt, *ast.ForStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt,
+			*ast.SelectStmt, *ast.CaseClause, *ast.CommClause:
+			frame.decls = map[string]*ast.Ident{}
+		case *ast.RangeStmt:
+			frame.decls = map[string]*ast.Ident{}
+			stack = append(stack, frame)
+			if n.Tok == token.DEFINE {
+				if id, ok := n.Key.(*ast.Ident); ok {
+					declare(id)
+				}
+				if id, ok := n.Value.(*ast.Ident); ok {
+					declare(id)
+				}
+			}
+			return true
+		case *ast.AssignStmt:
+			// the variables aren't in scope until the statement ends, so
+			// the right hand side is walked before they are declared
+			for _, rhs := range n.Rhs {
+				ast.Inspect(rhs, visit)
+			}
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						declare(id)
+					}
+				}
+			}
+			return false
+		case *ast.ValueSpec:
+			for _, v := range n.Values {
+				ast.Inspect(v, visit)
+			}
+			for _, name := range n.Names {
+				declare(name)
+			}
+			return false
+		}
+		stack = append(stack, frame)
+		return true
+	}
+	ast.Inspect(fn, visit)
+	return out
+}
+
+// deferredResultAssigns reports named results that are assigned from within
+// a deferred function literal.
+func deferredResultAssigns(fset *token.FileSet, fn *ast.FuncDecl) []Warning {
+	results := map[*ast.Object]bool{}
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			for _, name := range field.Names {
+				if name.Obj != nil {
+					results[name.Obj] = true
+				}
+			}
+		}
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	out := []Warning{}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		ds, ok := n.(*ast.DeferStmt)
+		if !ok {
+			return true
+		}
+		lit, ok := ds.Call.Fun.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			as, ok := n.(*ast.AssignStmt)
+			if !ok || as.Tok == token.DEFINE {
+				return true
+			}
+			for _, lhs := range as.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && results[id.Obj] {
+					out = append(out, Warning{
+						Check:   "deferred-result",
+						Pos:     fset.Position(id.Pos()),
+						Message: fmt.Sprintf("named result %q of %s is reassigned in a deferred closure", id.Name, fn.Name.Name),
+					})
+				}
+			}
+			return true
+		})
+		return false
+	})
+	return out
+}
@@ -0,0 +1,67 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"unicode/utf8"
+)
+
+// Signature is the rendered declaration of an exported function or method.
+type Signature struct {
+	Func      string         `json:"func"`
+	Signature string         `json:"signature"`
+	Length    int            `json:"length"`
+	Pos       token.Position `json:"position"`
+}
+
+// signatures renders the exported functions and methods of a package so
+// that unwieldy ones can be found by their length.
+type signatures struct {
+	funcs []Signature
+}
+
+func (m *signatures) Name() string { return SignaturesMetric }
+
+func (m *signatures) Description() string {
+	return "Rendered signatures of exported functions. Very long ones take too many parameters and may want a struct or options."
+}
+
+func (m *signatures) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || (fn.Recv != nil && !ast.IsExported(receiverName(fn))) {
+			continue
+		}
+		sig := funcSignature(fn)
+		m.funcs = append(m.funcs, Signature{
+			Func:      funcName(fn),
+			Signature: sig,
+			Length:    utf8.RuneCountInString(sig),
+			Pos:       fset.Position(fn.Name.Pos()),
+		})
+	}
+}
+
+// Result returns the Signatures in source order.
+func (m *signatures) Result() interface{} {
+	return append([]Signature{}, m.funcs...)
+}
+
+func (m *signatures) Merge(results []interface{}) {
+	for _, r := range results {
+		m.funcs = append(m.funcs, r.([]Signature)...)
+	}
+}
+
+// LongSignatures returns the signatures longer than limit, longest first.
+func LongSignatures(sigs []Signature, limit int) []Signature {
+	out := []Signature{}
+	for _, s := range sigs {
+		if s.Length > limit {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Length > out[j].Length })
+	return out
+}
@@ -0,0 +1,67 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// SliceParams counts the exported functions and methods taking parameters by
+// whether any of them is a slice, telling an API built around batches from
+// one taking an item at a time. Variadic parameters aren't counted as
+// slices.
+type SliceParams struct {
+	// Slices take at least one slice parameter, Single only take other
+	// parameters. Functions without parameters are in neither.
+	Slices int `json:"slices"`
+	Single int `json:"single"`
+}
+
+// SliceRatio returns the fraction of the functions taking parameters that
+// take a slice, or 0 if none take parameters.
+func (s SliceParams) SliceRatio() float64 {
+	if s.Slices+s.Single == 0 {
+		return 0
+	}
+	return float64(s.Slices) / float64(s.Slices+s.Single)
+}
+
+type sliceParams struct {
+	r SliceParams
+}
+
+func (m *sliceParams) Name() string { return SliceParamsMetric }
+
+func (m *sliceParams) Description() string {
+	return "Exported functions taking slice parameters against those taking single values, whether the API favours batch or single item operations."
+}
+
+func (m *sliceParams) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || fn.Type.Params.NumFields() == 0 {
+			continue
+		}
+		slices := false
+		for _, p := range fn.Type.Params.List {
+			if at, ok := p.Type.(*ast.ArrayType); ok && at.Len == nil {
+				slices = true
+			}
+		}
+		if slices {
+			m.r.Slices++
+		} else {
+			m.r.Single++
+		}
+	}
+}
+
+// Result returns the SliceParams of the package.
+func (m *sliceParams) Result() interface{} { return m.r }
+
+func (m *sliceParams) Merge(results []interface{}) {
+	for _, r := range results {
+		s := r.(SliceParams)
+		m.r.Slices += s.Slices
+		m.r.Single += s.Single
+	}
+}
@@ -0,0 +1,105 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// structTags counts the fields of exported structs carrying each struct tag
+// key, such as json or xml.
+type structTags struct {
+	counts map[string]int
+}
+
+func (m *structTags) Name() string { return StructTagsMetric }
+
+func (m *structTags) Description() string {
+	return "Struct tag keys used on exported structs, showing how types are serialised."
+}
+
+func (m *structTags) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		d, ok := d.(*ast.GenDecl)
+		if !ok || d.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range d.Specs {
+			ts, ok := s.(*ast.TypeSpec)
+			if !ok || !ts.Name.IsExported() {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				n := len(field.Names)
+				if n == 0 {
+					n = 1 // embedded
+				}
+				for _, key := range tagKeys(reflect.StructTag(tag)) {
+					m.counts[key] += n
+				}
+			}
+		}
+	}
+}
+
+// Result returns the number of fields tagged with each key.
+func (m *structTags) Result() interface{} {
+	out := map[string]int{}
+	for k, v := range m.counts {
+		out[k] = v
+	}
+	return out
+}
+
+func (m *structTags) Merge(results []interface{}) {
+	for _, r := range results {
+		for k, n := range r.(map[string]int) {
+			m.counts[k] += n
+		}
+	}
+}
+
+// tagKeys returns the keys of a struct tag in the conventional
+// key:"value" form, stopping at the first malformed pair as reflect does.
+func tagKeys(tag reflect.StructTag) []string {
+	keys := []string{}
+	s := strings.TrimSpace(string(tag))
+	for s != "" {
+		i := strings.Index(s, `:"`)
+		if i <= 0 || strings.ContainsAny(s[:i], " \t\"") {
+			break
+		}
+		key := s[:i]
+		if _, ok := tag.Lookup(key); !ok {
+			break
+		}
+		keys = append(keys, key)
+
+		// skip past the quoted value, minding escaped quotes
+		j := i + 2
+		for j < len(s) && s[j] != '"' {
+			if s[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(s) {
+			break
+		}
+		s = strings.TrimSpace(s[j+1:])
+	}
+	return keys
+}
@@ -0,0 +1,108 @@
+package analyser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// stubs flags exported functions whose body is a single panic, or a single
+// return of zero values next to a TODO comment. Either suggests an API that
+// has been declared but not yet implemented.
+type stubs struct {
+	warnings []Warning
+}
+
+func (m *stubs) Name() string { return StubsMetric }
+
+func (m *stubs) Description() string {
+	return "Exported functions that only panic or return zero values marked TODO, an API declared but not yet implemented."
+}
+
+func (m *stubs) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || len(fn.Body.List) != 1 || !fn.Name.IsExported() {
+			continue
+		}
+
+		var reason string
+		switch s := fn.Body.List[0].(type) {
+		case *ast.ExprStmt:
+			if isPanic(s.X) {
+				reason = "only panics"
+			}
+		case *ast.ReturnStmt:
+			if returnsZeroValues(s) && hasTODO(f, fn) {
+				reason = "only returns zero values and is marked TODO"
+			}
+		}
+		if reason == "" {
+			continue
+		}
+		m.warnings = append(m.warnings, Warning{
+			Check:   "stub",
+			Pos:     fset.Position(fn.Name.Pos()),
+			Message: fmt.Sprintf("%s %s, it may be a stub", funcName(fn), reason),
+		})
+	}
+}
+
+// Result returns a Warning for every likely stub.
+func (m *stubs) Result() interface{} {
+	return append([]Warning{}, m.warnings...)
+}
+
+func (m *stubs) Merge(results []interface{}) {
+	for _, r := range results {
+		m.warnings = append(m.warnings, r.([]Warning)...)
+	}
+}
+
+func isPanic(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	id, ok := call.Fun.(*ast.Ident)
+	return ok && id.Name == "panic"
+}
+
+// returnsZeroValues reports whether every result of s is nil, 0, "", false
+// or an empty composite literal. A bare return counts too.
+func returnsZeroValues(s *ast.ReturnStmt) bool {
+	for _, r := range s.Results {
+		switch r := r.(type) {
+		case *ast.Ident:
+			if r.Name != "nil" && r.Name != "false" {
+				return false
+			}
+		case *ast.BasicLit:
+			if r.Value != "0" && r.Value != `""` && r.Value != "``" {
+				return false
+			}
+		case *ast.CompositeLit:
+			if len(r.Elts) > 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// hasTODO reports whether fn's doc comment or any comment inside its body
+// mentions TODO.
+func hasTODO(f *ast.File, fn *ast.FuncDecl) bool {
+	if fn.Doc != nil && strings.Contains(fn.Doc.Text(), "TODO") {
+		return true
+	}
+	for _, c := range f.Comments {
+		if c.Pos() >= fn.Body.Pos() && c.End() <= fn.Body.End() && strings.Contains(c.Text(), "TODO") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// Type is an exported type along with the exported methods declared on it.
+type Type struct {
+	Name string `json:"name"`
+	// Kind is interface, struct, func, map, slice, array, chan, pointer or,
+	// for a type defined in terms of another named type, named.
+	Kind    string   `json:"kind"`
+	Methods []string `json:"methods"`
+	// Embeds are the types embedded in a struct or interface, such as Base
+	// or io.Reader, without any pointer.
+	Embeds []string `json:"embeds"`
+}
+
+// exportedTypes collects the exported types declared across a package and groups
+// exported methods under the type of their receiver.
+type exportedTypes struct {
+	declared []string
+	kinds    map[string]string
+	methods  map[string][]string
+	embeds   map[string][]string
+}
+
+func newExportedTypes() *exportedTypes {
+	return &exportedTypes{kinds: map[string]string{}, methods: map[string][]string{}, embeds: map[string][]string{}}
+}
+
+func (m *exportedTypes) Name() string { return TypesMetric }
+
+func (m *exportedTypes) Description() string {
+	return "Exported types and their exported methods. A type with far more methods than the rest may be doing too much."
+}
+
+func (m *exportedTypes) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range d.Specs {
+				if ts, ok := s.(*ast.TypeSpec); ok && ts.Name.IsExported() {
+					m.declared = append(m.declared, ts.Name.Name)
+					m.kinds[ts.Name.Name] = typeKind(ts.Type)
+					m.embeds[ts.Name.Name] = embeddedTypes(ts.Type)
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || !d.Name.IsExported() {
+				continue
+			}
+			if recv := receiverName(d); recv != "" {
+				m.methods[recv] = append(m.methods[recv], d.Name.Name)
+			}
+		}
+	}
+}
+
+// Result returns the exported types sorted by name.
+func (m *exportedTypes) Result() interface{} {
+	sort.Strings(m.declared)
+	out := []Type{}
+	for _, name := range m.declared {
+		methods := append([]string{}, m.methods[name]...)
+		sort.Strings(methods)
+		embeds := append([]string{}, m.embeds[name]...)
+		out = append(out, Type{Name: name, Kind: m.kinds[name], Methods: methods, Embeds: embeds})
+	}
+	return out
+}
+
+// typeKind classifies the type expression of a type declaration.
+func typeKind(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.StructType:
+		return "struct"
+	case *ast.FuncType:
+		return "func"
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "slice"
+		}
+		return "array"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.StarExpr:
+		return "pointer"
+	case *ast.ParenExpr:
+		return typeKind(e.X)
+	default:
+		return "named"
+	}
+}
+
+// embeddedTypes returns the types embedded in a struct or interface type.
+func embeddedTypes(expr ast.Expr) []string {
+	var fields *ast.FieldList
+	switch t := expr.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields = t.Methods
+	default:
+		return nil
+	}
+
+	out := []string{}
+	for _, field := range fields.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		switch typ.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			out = append(out, types.ExprString(typ))
+		default:
+			// a union or approximation in a type constraint
+		}
+	}
+	return out
+}
+
+// InterfaceRatio counts the interface and concrete types among types.
+func InterfaceRatio(types []Type) (interfaces, concrete int) {
+	for _, t := range types {
+		if t.Kind == "interface" {
+			interfaces++
+		} else {
+			concrete++
+		}
+	}
+	return interfaces, concrete
+}
+
+// receiverName returns the name of the type a method is declared on,
+// dereferencing pointer receivers and dropping any type parameters.
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	return namedType(fn.Recv.List[0].Type)
+}
+
+// ByMethodCount returns the types with exported methods, those with the most
+// first, to show which types dominate the API.
+func ByMethodCount(types []Type) []Type {
+	out := []Type{}
+	for _, t := range types {
+		if len(t.Methods) > 0 {
+			out = append(out, t)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i].Methods) > len(out[j].Methods) })
+	return out
+}
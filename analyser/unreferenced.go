@@ -0,0 +1,66 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// unreferenced finds the exported functions that are never referred to from
+// elsewhere in the package. Callers outside the package can't be seen, so
+// these are either API-only or dead.
+type unreferenced struct {
+	exported   map[string]bool
+	referenced map[string]bool
+}
+
+func newUnreferenced() *unreferenced {
+	return &unreferenced{exported: map[string]bool{}, referenced: map[string]bool{}}
+}
+
+func (m *unreferenced) Name() string { return UnreferencedMetric }
+
+func (m *unreferenced) Description() string {
+	return "Exported functions not referred to within the package. They are either only used by callers, or dead."
+}
+
+func (m *unreferenced) Process(fset *token.FileSet, f *ast.File) {
+	selected := map[*ast.Ident]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			selected[sel.Sel] = true
+		}
+		return true
+	})
+
+	for _, d := range f.Decls {
+		fn, isFn := d.(*ast.FuncDecl)
+		if isFn && fn.Recv == nil && fn.Name.IsExported() {
+			m.exported[fn.Name.Name] = true
+		}
+		ast.Inspect(d, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok || selected[id] {
+				return true
+			}
+			if isFn && (id == fn.Name || (fn.Recv == nil && id.Name == fn.Name.Name)) {
+				// a function's own name and recursive calls don't count
+				return true
+			}
+			m.referenced[id.Name] = true
+			return true
+		})
+	}
+}
+
+// Result returns the sorted names of the unreferenced exported functions.
+func (m *unreferenced) Result() interface{} {
+	out := []string{}
+	for name := range m.exported {
+		if !m.referenced[name] {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,82 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+)
+
+// RiskyPackages are packages whose use deserves a closer look during review.
+var RiskyPackages = []string{"reflect", "unsafe"}
+
+// PackageUsage counts the places a package is referred to.
+type PackageUsage struct {
+	Sites int `json:"sites"`
+	Files int `json:"files"`
+}
+
+// localName returns the name a file refers to an imported package by, or ""
+// if the file doesn't import it.
+func localName(f *ast.File, imported string) string {
+	for _, i := range f.Imports {
+		p := importPath(i)
+		if p != imported {
+			continue
+		}
+		if i.Name != nil {
+			return i.Name.Name
+		}
+		return path.Base(p)
+	}
+	return ""
+}
+
+// selectorsOf counts the selector expressions in f, such as reflect.ValueOf,
+// that refer to the package imported as name.
+func selectorsOf(f *ast.File, name string) int {
+	sites := 0
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && id.Name == name && id.Obj == nil {
+			sites++
+		}
+		return true
+	})
+	return sites
+}
+
+// riskyPackages counts the places each of RiskyPackages is used.
+type riskyPackages struct {
+	usage map[string]PackageUsage
+}
+
+func newRiskyPackages() *riskyPackages {
+	return &riskyPackages{usage: map[string]PackageUsage{}}
+}
+
+func (m *riskyPackages) Name() string { return RiskyPackagesMetric }
+
+func (m *riskyPackages) Description() string {
+	return "Uses of packages such as reflect and unsafe, which deserve a closer look during review."
+}
+
+func (m *riskyPackages) Process(fset *token.FileSet, f *ast.File) {
+	for _, p := range RiskyPackages {
+		name := localName(f, p)
+		if name == "" || name == "_" || name == "." {
+			continue
+		}
+		if sites := selectorsOf(f, name); sites > 0 {
+			u := m.usage[p]
+			u.Sites += sites
+			u.Files++
+			m.usage[p] = u
+		}
+	}
+}
+
+// Result returns the PackageUsage of every risky package that is used.
+func (m *riskyPackages) Result() interface{} { return m.usage }
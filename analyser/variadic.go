@@ -0,0 +1,75 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// VariadicFunc is an exported function whose last parameter is variadic.
+type VariadicFunc struct {
+	Func string `json:"func"`
+	// Elem is the type of each variadic argument.
+	Elem string `json:"elem"`
+	// Options is set for the functional options pattern, a variadic
+	// parameter of a type named like Option.
+	Options bool           `json:"options"`
+	Pos     token.Position `json:"position"`
+}
+
+// variadic finds the exported functions and methods taking a variadic
+// final parameter.
+type variadic struct {
+	funcs []VariadicFunc
+}
+
+func (m *variadic) Name() string { return VariadicMetric }
+
+func (m *variadic) Description() string {
+	return "Exported functions taking a variadic parameter."
+}
+
+func (m *variadic) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || fn.Type.Params.NumFields() == 0 {
+			continue
+		}
+		params := fn.Type.Params.List
+		ellipsis, ok := params[len(params)-1].Type.(*ast.Ellipsis)
+		if !ok {
+			continue
+		}
+		m.funcs = append(m.funcs, VariadicFunc{
+			Func:    funcName(fn),
+			Elem:    types.ExprString(ellipsis.Elt),
+			Options: isOptionType(ellipsis.Elt),
+			Pos:     fset.Position(fn.Name.Pos()),
+		})
+	}
+}
+
+// Result returns the variadic functions in source order.
+func (m *variadic) Result() interface{} {
+	return append([]VariadicFunc{}, m.funcs...)
+}
+
+func (m *variadic) Merge(results []interface{}) {
+	for _, r := range results {
+		m.funcs = append(m.funcs, r.([]VariadicFunc)...)
+	}
+}
+
+// isOptionType reports whether a type is named like an option, such as
+// Option, ClientOption or grpc.DialOption.
+func isOptionType(expr ast.Expr) bool {
+	name := ""
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	}
+	return strings.HasSuffix(name, "Option") || strings.HasSuffix(name, "Opt")
+}
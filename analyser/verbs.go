@@ -0,0 +1,63 @@
+package analyser
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// VerbCount is how many exported functions and methods have a name starting
+// with a word, such as Get in GetUser.
+type VerbCount struct {
+	Verb  string `json:"verb"`
+	Count int    `json:"count"`
+}
+
+// nameVerbs groups exported functions and methods by the first word of their
+// name, usually a verb: an API of Get and Set reads differently from one of
+// New and Parse.
+type nameVerbs struct {
+	counts map[string]int
+}
+
+func newNameVerbs() *nameVerbs {
+	return &nameVerbs{counts: map[string]int{}}
+}
+
+func (m *nameVerbs) Name() string { return NameVerbsMetric }
+
+func (m *nameVerbs) Description() string {
+	return "Exported functions and methods grouped by the first word of their name, such as Get, Set or New, sketching what the API does."
+}
+
+func (m *nameVerbs) Process(fset *token.FileSet, f *ast.File) {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Name.IsExported() {
+			m.counts[splitCamel(fn.Name.Name)[0]]++
+		}
+	}
+}
+
+// Result returns the VerbCount of every leading word, the most frequent
+// first.
+func (m *nameVerbs) Result() interface{} {
+	out := []VerbCount{}
+	for verb, n := range m.counts {
+		out = append(out, VerbCount{Verb: verb, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Verb < out[j].Verb
+	})
+	return out
+}
+
+func (m *nameVerbs) Merge(results []interface{}) {
+	for _, r := range results {
+		for _, v := range r.([]VerbCount) {
+			m.counts[v.Verb] += v.Count
+		}
+	}
+}
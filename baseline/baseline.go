@@ -0,0 +1,78 @@
+// Package baseline compares package metrics with those of the Go standard
+// library.
+//
+// The distributions are precomputed from GOROOT/src by gen.go and embedded in
+// baseline.json; regenerate them with go generate.
+package baseline
+
+//go:generate go run gen.go
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// Names of the values compared with the baseline.
+const (
+	ExportedFuncsPerFile = "exported functions per file"
+	ExportedFuncs        = "exported functions"
+	LinesPerFile         = "lines per file"
+	Imports              = "imports"
+)
+
+// Names lists every compared value in the order they should be reported.
+var Names = []string{ExportedFuncsPerFile, ExportedFuncs, LinesPerFile, Imports}
+
+// Baseline holds, for each value, its 0th to 100th percentile across the
+// packages of the standard library.
+type Baseline struct {
+	GoVersion   string               `json:"goVersion"`
+	Packages    int                  `json:"packages"`
+	Percentiles map[string][]float64 `json:"percentiles"`
+}
+
+//go:embed baseline.json
+var data []byte
+
+// Load returns the embedded standard library baseline.
+func Load() (Baseline, error) {
+	var b Baseline
+	err := json.Unmarshal(data, &b)
+	return b, err
+}
+
+// Values extracts the values compared with the baseline from a result.
+func Values(r analyser.Result) map[string]float64 {
+	exported, _ := r.Metrics[analyser.ExportedFuncsMetric].(analyser.ExportedFuncs)
+	lines, _ := r.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+	imports, _ := r.Metrics[analyser.ImportsMetric].([]string)
+
+	perFile := 0.
+	if r.Files > 0 {
+		perFile = float64(exported.Total) / float64(r.Files)
+	}
+	return map[string]float64{
+		ExportedFuncsPerFile: perFile,
+		ExportedFuncs:        float64(exported.Total),
+		LinesPerFile:         lines.Mean,
+		Imports:              float64(len(imports)),
+	}
+}
+
+// Percentile returns the percentile of the standard library v falls in for
+// the named value.
+func (b Baseline) Percentile(name string, v float64) (int, bool) {
+	q, ok := b.Percentiles[name]
+	if !ok || len(q) == 0 {
+		return 0, false
+	}
+	below := sort.Search(len(q), func(i int) bool { return q[i] > v })
+	p := (below - 1) * 100 / (len(q) - 1)
+	if p < 0 {
+		p = 0
+	}
+	return p, true
+}
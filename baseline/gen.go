@@ -0,0 +1,88 @@
+//go:build ignore
+// +build ignore
+
+// gen computes the standard library baseline from GOROOT/src.
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+	"github.com/trelore/package-analyser/baseline"
+)
+
+func main() {
+	root := filepath.Join(runtime.GOROOT(), "src")
+	values := map[string][]float64{}
+	packages := 0
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		switch d.Name() {
+		case "cmd", "internal", "testdata", "vendor":
+			return filepath.SkipDir
+		}
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil
+		}
+		for name, pkg := range pkgs {
+			if name == "main" || strings.HasSuffix(name, "_test") {
+				continue
+			}
+			files := []*ast.File{}
+			for _, f := range pkg.Files {
+				files = append(files, f)
+			}
+			for k, v := range baseline.Values(analyser.Analyse(fset, files)) {
+				values[k] = append(values[k], v)
+			}
+			packages++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	b := baseline.Baseline{
+		GoVersion:   runtime.Version(),
+		Packages:    packages,
+		Percentiles: map[string][]float64{},
+	}
+	for k, vs := range values {
+		sort.Float64s(vs)
+		q := make([]float64, 101)
+		for p := range q {
+			i := int(math.Ceil(float64(p)/100*float64(len(vs)))) - 1
+			if i < 0 {
+				i = 0
+			}
+			q[p] = math.Round(vs[i]*100) / 100
+		}
+		b.Percentiles[k] = q
+	}
+
+	out, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("baseline.json", append(out, '\n'), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
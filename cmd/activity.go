@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// addActivity looks up the last commit to each file of the results, which
+// costs an API call per file, and records the newest and oldest.
+func addActivity(ctx context.Context, client *github.Client, loc githubLocation, results []analyser.Result) error {
+	for i, r := range results {
+		lines, _ := r.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+		var activity *analyser.Activity
+		for _, fl := range lines.Files {
+			d, err := lastCommitDate(ctx, client, loc, fl.File)
+			if err != nil {
+				return err
+			}
+			fd := analyser.FileDate{File: fl.File, Date: d}
+			if activity == nil {
+				activity = &analyser.Activity{Newest: fd, Oldest: fd}
+				continue
+			}
+			if d.After(activity.Newest.Date) {
+				activity.Newest = fd
+			}
+			if d.Before(activity.Oldest.Date) {
+				activity.Oldest = fd
+			}
+		}
+		results[i].Activity = activity
+	}
+	return nil
+}
+
+// lastCommitDate returns when the last commit touching file was made.
+func lastCommitDate(ctx context.Context, client *github.Client, loc githubLocation, file string) (time.Time, error) {
+	defer startPhase(phaseFetch)()
+	opts := &github.CommitsListOptions{SHA: loc.ref, Path: file, ListOptions: github.ListOptions{PerPage: 1}}
+	commits, _, err := client.Repositories.ListCommits(ctx, loc.owner, loc.repo, opts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("listing commits of %s: %w", file, err)
+	}
+	if len(commits) == 0 {
+		return time.Time{}, fmt.Errorf("no commits found for %s", file)
+	}
+	return commits[0].GetCommit().GetCommitter().GetDate(), nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/bgentry/go-netrc/netrc"
+)
+
+// githubToken resolves the token used to authenticate with GitHub. It's the
+// first of, in order:
+//
+//  1. the --token flag
+//  2. the GITHUB_TOKEN environment variable
+//  3. the token of an authenticated gh CLI, or failing that the token in
+//     gh's hosts.yml
+//  4. the password of the github.com machine in the netrc file
+//
+// An empty token means requests are made anonymously.
+func githubToken() string {
+	if tokenFlag != "" {
+		return tokenFlag
+	}
+	if t := os.Getenv("GITHUB_TOKEN"); t != "" {
+		return t
+	}
+	if t := ghCLIToken(); t != "" {
+		return t
+	}
+	if t := ghHostsToken(); t != "" {
+		return t
+	}
+	return netrcToken()
+}
+
+// ghCLIToken asks the gh CLI for its token, returning "" if gh isn't
+// installed or isn't logged in.
+func ghCLIToken() string {
+	out, err := exec.Command("gh", "auth", "token", "--hostname", "github.com").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ghConfigDir returns the directory gh keeps its configuration in.
+func ghConfigDir() string {
+	if d := os.Getenv("GH_CONFIG_DIR"); d != "" {
+		return d
+	}
+	if d := os.Getenv("XDG_CONFIG_HOME"); d != "" {
+		return filepath.Join(d, "gh")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// ghHostsToken reads the oauth_token of github.com from gh's hosts.yml. Only
+// the simple layout gh writes is understood, so anything else yields "".
+func ghHostsToken() string {
+	dir := ghConfigDir()
+	if dir == "" {
+		return ""
+	}
+	f, err := os.Open(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inHost := false
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inHost = strings.TrimSpace(line) == "github.com:"
+			continue
+		}
+		if !inHost {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && key == "oauth_token" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// netrcFile returns the path of the netrc file, $NETRC if set and otherwise
+// .netrc, or _netrc on Windows, in the home directory.
+func netrcFile() string {
+	if f := os.Getenv("NETRC"); f != "" {
+		return f
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name)
+}
+
+// netrcToken reads the password of the github.com machine from the netrc
+// file. The default machine is ignored so that credentials meant for some
+// other host aren't sent to GitHub.
+func netrcToken() string {
+	name := netrcFile()
+	if name == "" {
+		return ""
+	}
+	for _, host := range []string{"github.com", "api.github.com"} {
+		m, err := netrc.FindMachine(name, host)
+		if err != nil {
+			return ""
+		}
+		if m != nil && !m.IsDefault() && m.Password != "" {
+			return m.Password
+		}
+	}
+	return ""
+}
+
+// tokenTransport authenticates every request with a GitHub token.
+type tokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+t.token)
+	return t.base.RoundTrip(req)
+}
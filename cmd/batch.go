@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+	"golang.org/x/sync/errgroup"
+)
+
+// target is everything found for one package argument, kept until it is
+// its turn to be output.
+type target struct {
+	// root is the directory or GitHub path file paths are shown relative to.
+	root    string
+	results []analyser.Result
+	// module is set when a local directory was analysed recursively.
+	module *moduleSummary
+}
+
+// moduleSummary describes the packages found by a recursive analysis.
+type moduleSummary struct {
+	packages []string
+	// cycles are only looked for when inModule is set.
+	cycles   [][]string
+	inModule bool
+	// excluded counts the packages skipped by --exclude-package.
+	excluded int
+}
+
+// analyseAll runs each package argument as of ref, at most limit at a time,
+// and returns the targets in the same order as pkgs.
+func analyseAll(pkgs []string, ref string, limit int) ([]target, error) {
+	targets := make([]target, len(pkgs))
+	var g errgroup.Group
+	if limit > 0 {
+		g.SetLimit(limit)
+	}
+	for i, pkg := range pkgs {
+		i, pkg := i, pkg
+		g.Go(func() error {
+			t, err := run(pkg, ref)
+			if err != nil {
+				return fmt.Errorf("analysing %s: %w", pkg, err)
+			}
+			targets[i] = t
+			return nil
+		})
+	}
+	return targets, g.Wait()
+}
+
+// selectPackages keeps only the results of packages named by --package,
+// ignoring case with --ignore-case.
+func selectPackages(targets []target) []target {
+	if len(packageFilter) == 0 {
+		return targets
+	}
+	for i := range targets {
+		kept := []analyser.Result{}
+		for _, r := range targets[i].results {
+			if nameMatches(r.Name, packageFilter) {
+				kept = append(kept, r)
+			}
+		}
+		targets[i].results = kept
+	}
+	return targets
+}
+
+// nameMatches reports whether name is one of names, ignoring case with
+// --ignore-case.
+func nameMatches(name string, names []string) bool {
+	for _, n := range names {
+		if n == name || (ignoreCase && strings.EqualFold(n, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPackageList reads package arguments from a file, one per line, ignoring
+// blank lines and lines starting with #.
+func readPackageList(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening package list: %w", err)
+	}
+	defer f.Close()
+
+	pkgs := []string{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pkgs = append(pkgs, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading package list: %w", err)
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// cacheFormat is part of every cache key, to be bumped should what is
+// cached change shape.
+const cacheFormat = 2
+
+// cachedAnalyseDir analyses the packages of a directory like analyseDir but,
+// with --cache, reuses the results of metrics computed by a previous run from
+// files that haven't changed since. Files are still parsed on every run.
+// Only the working tree is cached, not sampled or type checked packages.
+func cachedAnalyseDir(src sourceTree, dir string, mod module, inModule bool) ([]dirPackage, error) {
+	wt, ok := src.(fsTree)
+	if !useCache || !ok || sampleSize > 0 {
+		return analyseDir(src, dir, mod, inModule, nil)
+	}
+
+	c, err := newResultCache(wt, dir)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := analyseDir(src, dir, mod, inModule, c)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.write(); err != nil {
+		return nil, err
+	}
+	return pkgs, nil
+}
+
+// analyseCached is analyse reusing the results held in c.
+func analyseCached(path string, fset *token.FileSet, files []*ast.File, c *resultCache) analyser.Result {
+	defer startPhase(phaseAnalyse)()
+	r := analyser.AnalyseCached(fset, files, c)
+	r.Path = path
+	return r
+}
+
+// resultCache is the analyser.Cache of --cache, keeping entries in the
+// user's cache directory. An entry is keyed by the names and contents of the
+// files its results were computed from, a single one for an
+// analyser.FileMetric, and holds the result of each metric computed from them
+// along with the metric's version.
+type resultCache struct {
+	dir string
+	// hashes are the hashes of the contents of the Go files analysed, by
+	// the names they are parsed with.
+	hashes map[string]string
+	// entries are those read or stored during the run, by key.
+	entries map[string]cacheEntry
+	// stored are the keys of the entries to write.
+	stored map[string]bool
+}
+
+// cacheEntry maps the name of a metric to its cached result.
+type cacheEntry map[string]cachedResult
+
+type cachedResult struct {
+	Version int             `json:"version"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// newResultCache returns the resultCache of the Go files in dir.
+func newResultCache(t fsTree, dir string) (*resultCache, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("finding cache directory: %w", err)
+	}
+	c := &resultCache{
+		dir:     filepath.Join(cacheDir, "package-analyser"),
+		hashes:  map[string]string{},
+		entries: map[string]cacheEntry{},
+		stored:  map[string]bool{},
+	}
+
+	p, err := t.path(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(t.fsys, p)
+	if err != nil {
+		return nil, t.osError(err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		b, err := fs.ReadFile(t.fsys, path.Join(p, e.Name()))
+		if err != nil {
+			return nil, t.osError(err)
+		}
+		sum := sha256.Sum256(b)
+		c.hashes[filepath.Join(dir, e.Name())] = hex.EncodeToString(sum[:])
+	}
+	return c, nil
+}
+
+// key returns the key of the entry for files, reporting false if one of them
+// wasn't hashed.
+func (c *resultCache) key(files []string) (string, bool) {
+	h := sha256.New()
+	fmt.Fprintln(h, cacheFormat)
+	for _, f := range files {
+		sum, ok := c.hashes[f]
+		if !ok {
+			return "", false
+		}
+		fmt.Fprintln(h, strconv.Quote(f), sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// entry returns the entry keyed key, read from the cache directory the first
+// time it's asked for. An entry that is missing or can't be decoded is empty.
+func (c *resultCache) entry(key string) cacheEntry {
+	if e, ok := c.entries[key]; ok {
+		return e
+	}
+	e := cacheEntry{}
+	if b, err := os.ReadFile(filepath.Join(c.dir, key+".json")); err == nil {
+		if err = json.Unmarshal(b, &e); err != nil {
+			e = cacheEntry{}
+		}
+	}
+	c.entries[key] = e
+	return e
+}
+
+func (c *resultCache) Load(m analyser.Metric, files []string) (interface{}, bool) {
+	key, ok := c.key(files)
+	if !ok {
+		return nil, false
+	}
+	cached, ok := c.entry(key)[m.Name()]
+	if !ok || cached.Version != analyser.Version(m) {
+		return nil, false
+	}
+	// the result of a fresh metric has the type to decode into; one
+	// returning nil has none, so its result is computed again
+	t := reflect.TypeOf(m.Result())
+	if t == nil {
+		return nil, false
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(cached.Result, v.Interface()); err != nil {
+		return nil, false
+	}
+	return v.Elem().Interface(), true
+}
+
+func (c *resultCache) Store(m analyser.Metric, files []string, result interface{}) {
+	key, ok := c.key(files)
+	if !ok {
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	c.entry(key)[m.Name()] = cachedResult{Version: analyser.Version(m), Result: b}
+	c.stored[key] = true
+}
+
+// write writes the entries results were stored in, each through a temporary
+// file so that concurrent runs never read a partial entry.
+func (c *resultCache) write() error {
+	if len(c.stored) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	for key := range c.stored {
+		b, err := json.Marshal(c.entries[key])
+		if err != nil {
+			return fmt.Errorf("encoding cache entry: %w", err)
+		}
+		f, err := os.CreateTemp(c.dir, "*.tmp")
+		if err != nil {
+			return fmt.Errorf("writing cache entry: %w", err)
+		}
+		if _, err = f.Write(b); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return fmt.Errorf("writing cache entry: %w", err)
+		}
+		if err = f.Close(); err != nil {
+			os.Remove(f.Name())
+			return fmt.Errorf("writing cache entry: %w", err)
+		}
+		if err = os.Rename(f.Name(), filepath.Join(c.dir, key+".json")); err != nil {
+			return fmt.Errorf("writing cache entry: %w", err)
+		}
+	}
+	c.stored = map[string]bool{}
+	return nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// writeCallGraph writes the calls between the functions of a package as a
+// Graphviz DOT digraph, one node per function.
+func writeCallGraph(w io.Writer, r analyser.Result) error {
+	g, _ := r.Metrics[analyser.CallGraphMetric].(analyser.CallGraph)
+
+	lines := []string{fmt.Sprintf("digraph %s {", strconv.Quote(r.Name))}
+	for _, f := range g.Funcs {
+		lines = append(lines, fmt.Sprintf("    %s;", strconv.Quote(f)))
+	}
+	for _, c := range g.Calls {
+		lines = append(lines, fmt.Sprintf("    %s -> %s;", strconv.Quote(c.Caller), strconv.Quote(c.Callee)))
+	}
+	lines = append(lines, "}")
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
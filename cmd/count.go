@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// checkCountMetric returns an error if --count-metric isn't a registered
+// metric, so that a typo fails before anything is fetched.
+func checkCountMetric() error {
+	for _, name := range analyser.Names() {
+		if name == countMetric {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown --count-metric %q", countMetric)
+}
+
+// metricCount reduces the result of a metric to a single number: the length
+// of a list or map, or the Total of a result that keeps one, such as the
+// number of exported functions.
+func metricCount(v interface{}) (int, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len(), true
+	case reflect.Struct:
+		if total := rv.FieldByName("Total"); total.IsValid() && total.Kind() == reflect.Int {
+			return int(total.Int()), true
+		}
+	}
+	return 0, false
+}
+
+// printCount prints the count of --count-metric summed across every package
+// analysed as a bare integer, for scripts to capture.
+func printCount(w io.Writer, targets []target) error {
+	n := 0
+	for _, t := range targets {
+		for _, r := range t.results {
+			c, ok := metricCount(r.Metrics[countMetric])
+			if !ok {
+				return fmt.Errorf("metric %s can't be counted, it is neither a list nor keeps a total", countMetric)
+			}
+			n += c
+		}
+	}
+	fmt.Fprintln(w, n)
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/trelore/package-analyser/analyser"
+	"golang.org/x/tools/cover"
+)
+
+// fileCoverage counts the statements of a file and those run by its tests.
+type fileCoverage struct {
+	statements, covered int
+}
+
+func (c fileCoverage) percent() float64 {
+	if c.statements == 0 {
+		return 0
+	}
+	return 100 * float64(c.covered) / float64(c.statements)
+}
+
+// coverage is loaded from --coverage, keyed by file names as the go tool
+// writes them: the import path of the package joined with the file's name.
+var coverage map[string]fileCoverage
+
+// loadCoverage reads a coverage profile written by go test -coverprofile.
+func loadCoverage(name string) (map[string]fileCoverage, error) {
+	profiles, err := cover.ParseProfiles(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading coverage profile: %w", err)
+	}
+	out := map[string]fileCoverage{}
+	for _, p := range profiles {
+		c := out[p.FileName]
+		for _, b := range p.Blocks {
+			c.statements += b.NumStmt
+			if b.Count > 0 {
+				c.covered += b.NumStmt
+			}
+		}
+		out[p.FileName] = c
+	}
+	return out, nil
+}
+
+// printCoverage lists the statement coverage of each file of a package next
+// to its number of exported functions. Files are matched to the profile by
+// import path, so only packages analysed within their module can be.
+func printCoverage(w io.Writer, r analyser.Result) {
+	if r.ImportPath == "" {
+		fmt.Fprintln(w, "Coverage unknown: the import path of the package isn't known")
+		return
+	}
+	exported, _ := r.Metrics[analyser.ExportedFuncsMetric].(analyser.ExportedFuncs)
+	lines, _ := r.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+
+	fmt.Fprintln(w, "Coverage per file:")
+	var total fileCoverage
+	// both metrics see the files in the same order
+	for i, f := range lines.Files {
+		funcs := 0
+		if i < len(exported.PerFile) {
+			funcs = int(exported.PerFile[i])
+		}
+		c, ok := coverage[path.Join(r.ImportPath, filepath.Base(f.File))]
+		if !ok {
+			fmt.Fprintf(w, "  %s: not in profile, %d exported function(s)\n", displayPath(f.File), funcs)
+			continue
+		}
+		total.statements += c.statements
+		total.covered += c.covered
+		fmt.Fprintf(w, "  %s: %.1f%% of %d statement(s) covered, %d exported function(s)\n", displayPath(f.File), c.percent(), c.statements, funcs)
+	}
+	if total.statements > 0 {
+		fmt.Fprintf(w, "  In total %.1f%% of %d statement(s) covered\n", total.percent(), total.statements)
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// driftCmd compares a local copy of a package with its upstream on GitHub
+var driftCmd = &cobra.Command{
+	Use:   "drift <local package> <github package>",
+	Short: "Reports how the exported API and imports of a local copy of a package diverge from upstream",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		local, upstream := args[0], args[1]
+		if isRemote(local) {
+			return fmt.Errorf("%s isn't a local package", local)
+		}
+		if !isRemote(upstream) {
+			return fmt.Errorf("%s isn't a GitHub package", upstream)
+		}
+		if metricsNDJSON || mermaid || dotCallGraph {
+			return fmt.Errorf("drift only prints text, --json or --json-pretty")
+		}
+		cmd.SilenceUsage = true
+
+		lt, err := run(local, "")
+		if err != nil {
+			return fmt.Errorf("analysing %s: %w", local, err)
+		}
+		ut, err := run(upstream, "")
+		if err != nil {
+			return fmt.Errorf("analysing %s: %w", upstream, err)
+		}
+
+		api := diffManifests(targetManifest(ut), targetManifest(lt))
+		imports := diffImports(targetImports(ut), targetImports(lt))
+		if jsonOut || jsonPretty {
+			enc := json.NewEncoder(os.Stdout)
+			if jsonPretty {
+				enc.SetIndent("", "  ")
+			}
+			return enc.Encode(drift{Local: local, Upstream: upstream, API: api, Imports: imports})
+		}
+		if len(api) == 0 && len(imports) == 0 {
+			fmt.Printf("%s has the same exported API and imports as %s\n", local, upstream)
+			return nil
+		}
+		fmt.Printf("%s has drifted from %s:\n", local, upstream)
+		if len(api) > 0 {
+			fmt.Println("Exported API:")
+			for _, d := range api {
+				fmt.Printf("  %s\n", d)
+			}
+		}
+		if len(imports) > 0 {
+			fmt.Println("Imports:")
+			for _, d := range imports {
+				fmt.Printf("  %s\n", d)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+}
+
+// drift is how a local package differs from upstream, as printed by --json.
+type drift struct {
+	Local    string   `json:"local"`
+	Upstream string   `json:"upstream"`
+	API      []string `json:"api"`
+	Imports  []string `json:"imports"`
+}
+
+// targetImports gathers the imports of each package of an analysed target,
+// keyed by package name.
+func targetImports(t target) map[string][]string {
+	out := map[string][]string{}
+	for _, r := range t.results {
+		imports, _ := r.Metrics[analyser.ImportsMetric].([]string)
+		out[r.Name] = imports
+	}
+	return out
+}
+
+// diffImports describes every import added to or removed from the packages
+// in got compared with want, as lines prefixed with + or -.
+func diffImports(want, got map[string][]string) []string {
+	names := map[string]bool{}
+	for name := range want {
+		names[name] = true
+	}
+	for name := range got {
+		names[name] = true
+	}
+
+	out := []string{}
+	for _, pkg := range sortedKeys(names) {
+		before, after := map[string]bool{}, map[string]bool{}
+		for _, i := range want[pkg] {
+			before[i] = true
+		}
+		for _, i := range got[pkg] {
+			after[i] = true
+		}
+		for _, i := range sortedKeys(after) {
+			if !before[i] {
+				out = append(out, fmt.Sprintf("+ %s: %q", pkg, i))
+			}
+		}
+		for _, i := range sortedKeys(before) {
+			if !after[i] {
+				out = append(out, fmt.Sprintf("- %s: %q", pkg, i))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// refTotals are the sizes of the packages analysed at one ref.
+type refTotals struct {
+	ref                                       string
+	packages, files, exported, lines, imports int
+}
+
+// totalsAt analyses pkgs as of ref and totals their results.
+func totalsAt(pkgs []string, ref string) (refTotals, error) {
+	targets, err := analyseAll(pkgs, ref, concurrency)
+	if err != nil {
+		return refTotals{}, fmt.Errorf("analysing %s: %w", ref, err)
+	}
+	targets = selectPackages(targets)
+
+	t := refTotals{ref: ref}
+	imports := map[string]bool{}
+	for _, tg := range targets {
+		for _, r := range tg.results {
+			t.packages++
+			t.files += r.Files
+			e, _ := r.Metrics[analyser.ExportedFuncsMetric].(analyser.ExportedFuncs)
+			t.exported += e.Total
+			l, _ := r.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+			for _, f := range l.Files {
+				t.lines += f.Lines
+			}
+			is, _ := r.Metrics[analyser.ImportsMetric].([]string)
+			for _, i := range is {
+				imports[i] = true
+			}
+		}
+	}
+	t.imports = len(imports)
+	return t, nil
+}
+
+// printEvolution analyses pkgs at each of refs in turn, as --ref would, and
+// prints a table of how their size changed from one ref to the next.
+func printEvolution(w io.Writer, pkgs, refs []string) error {
+	totals := []refTotals{}
+	for _, ref := range refs {
+		t, err := totalsAt(pkgs, ref)
+		if err != nil {
+			return err
+		}
+		totals = append(totals, t)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REF\tPACKAGES\tFILES\tEXPORTED FUNCS\tLINES\tIMPORTS")
+	for i, t := range totals {
+		prev := t
+		if i > 0 {
+			prev = totals[i-1]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ref,
+			change(t.packages, prev.packages), change(t.files, prev.files), change(t.exported, prev.exported),
+			change(t.lines, prev.lines), change(t.imports, prev.imports))
+	}
+	return tw.Flush()
+}
+
+// change formats n along with how it differs from prev, if at all.
+func change(n, prev int) string {
+	if n == prev {
+		return fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%d (%+d)", n, n-prev)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// formatEnv names the environment variable setting the default output format.
+const formatEnv = "PACKAGE_ANALYSER_FORMAT"
+
+// formats are the values formatEnv accepts, each named after the flag it
+// sets. text is the default of printing results for people to read.
+var formats = []struct {
+	name  string
+	value *bool
+}{
+	{"text", nil},
+	{"json", &jsonOut},
+	{"json-pretty", &jsonPretty},
+	{"metrics-ndjson", &metricsNDJSON},
+	{"mermaid", &mermaid},
+	{"call-graph", &dotCallGraph},
+}
+
+// applyFormatEnv sets the output format named by $PACKAGE_ANALYSER_FORMAT,
+// unless one is chosen on the command line.
+func applyFormatEnv(cmd *cobra.Command) error {
+	env := os.Getenv(formatEnv)
+	if env == "" {
+		return nil
+	}
+	names := []string{}
+	var value *bool
+	found := false
+	for _, f := range formats {
+		names = append(names, f.name)
+		if f.name == env {
+			value, found = f.value, true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown %s %q, expected one of %s", formatEnv, env, strings.Join(names, ", "))
+	}
+
+	for _, f := range formats {
+		if f.value != nil && cmd.Flags().Changed(f.name) {
+			return nil
+		}
+	}
+	if value != nil {
+		*value = true
+	}
+	return nil
+}
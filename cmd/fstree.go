@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fsTree reads files from a file system, by default the directory being
+// analysed on disk as it is now. Directories are named by joining their path
+// within fsys to root, the path fsys is found at, so that files are named as
+// they would be on disk.
+type fsTree struct {
+	fsys fs.FS
+	root string
+}
+
+// newWorktree returns the fsTree of the directory root on disk.
+func newWorktree(root string) fsTree {
+	return fsTree{fsys: os.DirFS(root), root: root}
+}
+
+// path returns the path of dir within the file system.
+func (t fsTree) path(dir string) (string, error) {
+	rel, err := filepath.Rel(t.root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside %s", dir, t.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// osError names the file of a path error as it would be named on disk rather
+// than by its path within the file system.
+func (t fsTree) osError(err error) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		return &fs.PathError{Op: pe.Op, Path: filepath.Join(t.root, filepath.FromSlash(pe.Path)), Err: pe.Err}
+	}
+	return err
+}
+
+// packageDirs returns root and every directory beneath it holding Go files,
+// skipping those the go tool ignores and, unless --include-vendor is set,
+// vendored dependencies.
+func (t fsTree) packageDirs(root string) ([]string, error) {
+	start, err := t.path(root)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{}
+	walk := func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != start && skipDir(d.Name()) {
+			return fs.SkipDir
+		}
+		entries, err := fs.ReadDir(t.fsys, p)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+				dir := root
+				if p != start {
+					dir = filepath.Join(t.root, filepath.FromSlash(p))
+				}
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+		return nil
+	}
+	if err := fs.WalkDir(t.fsys, start, walk); err != nil {
+		return nil, fmt.Errorf("walking %s: %w", root, t.osError(err))
+	}
+	return dirs, nil
+}
+
+func (t fsTree) parseDir(fset *token.FileSet, dir string) (map[string]*ast.Package, []string, error) {
+	p, err := t.path(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := fs.ReadDir(t.fsys, p)
+	if err != nil {
+		return nil, nil, t.osError(err)
+	}
+
+	pkgs := map[string]*ast.Package{}
+	names := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		names = append(names, e.Name())
+		if !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+
+		src, err := fs.ReadFile(t.fsys, path.Join(p, e.Name()))
+		if err != nil {
+			return nil, nil, t.osError(err)
+		}
+		filename := filepath.Join(dir, e.Name())
+		f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pkg, ok := pkgs[f.Name.Name]
+		if !ok {
+			pkg = &ast.Package{Name: f.Name.Name, Files: map[string]*ast.File{}}
+			pkgs[f.Name.Name] = pkg
+		}
+		pkg.Files[filename] = f
+	}
+	return pkgs, names, nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// gistID extracts the ID from a gist URL such as gist.github.com/user/<id>
+// or, for anonymous gists, gist.github.com/<id>.
+func gistID(pkg string) (string, error) {
+	s := []string{}
+	for _, seg := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(pkg, "https://"), "http://"), "/") {
+		if seg != "" {
+			s = append(s, seg)
+		}
+	}
+	if len(s) < 2 {
+		return "", fmt.Errorf("gist not specified in %s", pkg)
+	}
+	return strings.TrimSuffix(s[len(s)-1], ".git"), nil
+}
+
+func parseGist(pkg string) (target, error) {
+	id, err := gistID(pkg)
+	if err != nil {
+		return target{}, err
+	}
+
+	client := githubClient()
+	stop := startPhase(phaseFetch)
+	gist, _, err := client.Gists.Get(context.Background(), id)
+	stop()
+	if err != nil {
+		return target{}, fmt.Errorf("getting gist: %w", err)
+	}
+
+	names := []string{}
+	for name := range gist.Files {
+		if strings.HasSuffix(string(name), ".go") {
+			names = append(names, string(name))
+		}
+	}
+	sort.Sort(alphabetical(names))
+
+	fset := token.NewFileSet() // positions are relative to fset
+	files := []*ast.File{}
+	for _, name := range names {
+		f := gist.Files[github.GistFilename(name)]
+		c := f.GetContent()
+		if f.Content == nil && f.GetRawURL() != "" {
+			stop := startPhase(phaseFetch)
+			c, err = fetchRaw(f.GetRawURL())
+			stop()
+			if err != nil {
+				return target{}, fmt.Errorf("getting file contents: %w", err)
+			}
+		}
+
+		stop := startPhase(phaseParse)
+		fp, err := parser.ParseFile(fset, name, c, parser.ParseComments)
+		stop()
+		if err != nil {
+			return target{}, fmt.Errorf("parsing file: %w", err)
+		}
+		files = append(files, fp)
+	}
+	if len(files) == 0 {
+		return target{}, fmt.Errorf("no go files found in %s", pkg)
+	}
+
+	return target{results: analysePackages(pkg, fset, files, false)}, nil
+}
+
+// fetchRaw downloads the body at url, used for gist files whose content
+// wasn't included in the API response and files given by URL.
+func fetchRaw(url string) (string, error) {
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// githubLocation identifies a directory within a GitHub repository. An empty
+// path refers to the root of the repository and an empty ref to its default
+// branch.
+type githubLocation struct {
+	owner, repo, path, ref string
+}
+
+// parseGithubLocation splits a package such as github.com/owner/repo/path/to/pkg
+// into its parts. A scheme is allowed and empty path segments are ignored.
+// URLs copied from the browser, github.com/owner/repo/tree/<ref>/path, read
+// the path at that ref, and those of a file, .../blob/<ref>/path/file.go,
+// its directory. Refs containing a slash can't be told apart from
+// the path, so they must be given with --ref instead.
+func parseGithubLocation(pkg string) (githubLocation, error) {
+	raw := pkg
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return githubLocation{}, fmt.Errorf("parsing url: %w", err)
+	}
+
+	s := []string{}
+	for _, seg := range strings.Split(u.Path, "/") {
+		if seg != "" {
+			s = append(s, seg)
+		}
+	}
+	if len(s) < 2 {
+		return githubLocation{}, fmt.Errorf("repository not specified in %s", pkg)
+	}
+
+	loc := githubLocation{owner: s[0], repo: s[1]}
+	rest := s[2:]
+	if len(rest) >= 2 && (rest[0] == "tree" || rest[0] == "blob") {
+		isFile := rest[0] == "blob"
+		loc.ref, rest = rest[1], rest[2:]
+		if isFile && len(rest) > 0 {
+			// the package is the directory holding the file
+			rest = rest[:len(rest)-1]
+		}
+	}
+	loc.path = strings.Join(rest, "/")
+	return loc, nil
+}
+
+// parseGithubPackage analyses a package on GitHub as of ref, which takes
+// precedence over one given in the URL, or if empty the URL's.
+func parseGithubPackage(pkg, ref string) (target, error) {
+	loc, err := parseGithubLocation(pkg)
+	if err != nil {
+		return target{}, err
+	}
+	if ref != "" {
+		loc.ref = ref
+	}
+
+	if recursive {
+		return parseGithubModule(pkg, loc)
+	}
+
+	client := githubClient()
+
+	stop := startPhase(phaseFetch)
+	opts := &github.RepositoryContentGetOptions{Ref: loc.ref}
+	_, dirC, _, err := client.Repositories.GetContents(context.Background(), loc.owner, loc.repo, loc.path, opts)
+	stop()
+	if err != nil {
+		return target{}, fmt.Errorf("getting package: %w", err)
+	}
+
+	fset := token.NewFileSet() // positions are relative to fset
+	files := []*ast.File{}
+	partial := false
+
+	otherFiles := map[string]int{}
+	for _, f := range dirC {
+		if f.GetType() == "file" {
+			countOtherFile(otherFiles, f.GetName())
+		}
+	}
+
+	goFiles := []*github.RepositoryContent{}
+	for _, f := range dirC {
+		if strings.HasSuffix(f.GetName(), ".go") {
+			goFiles = append(goFiles, f)
+		}
+	}
+	sampled := sampleIndices(len(goFiles))
+
+	for _, i := range sampled {
+		f := goFiles[i]
+		if maxFiles > 0 && len(files) == maxFiles {
+			partial = true
+			break
+		}
+		stop := startPhase(phaseFetch)
+		fileC, _, _, err := client.Repositories.GetContents(context.Background(), loc.owner, loc.repo, f.GetPath(), opts)
+		stop()
+		if err != nil {
+			return target{}, fmt.Errorf("getting file: %w", err)
+		}
+		c, err := fileC.GetContent()
+		if err != nil {
+			return target{}, fmt.Errorf("getting file contents: %w", err)
+		}
+
+		stop = startPhase(phaseParse)
+		fp, err := parser.ParseFile(fset, f.GetPath(), c, parser.ParseComments)
+		stop()
+		if err != nil {
+			return target{}, fmt.Errorf("parsing file: %w", err)
+		}
+		files = append(files, fp)
+	}
+	if len(files) == 0 {
+		return target{}, fmt.Errorf("no go files found in %s", pkg)
+	}
+
+	results := analysePackages(pkg, fset, files, partial)
+	for i := range results {
+		results[i].OtherFiles = otherFiles
+		if len(sampled) < len(goFiles) {
+			results[i].SampledFrom = len(goFiles)
+		}
+	}
+	if showActivity {
+		if err := addActivity(context.Background(), client, loc, results); err != nil {
+			return target{}, err
+		}
+	}
+	return target{root: loc.path, results: results}, nil
+}
+
+// githubClient returns a GitHub client, authenticated if a token is found.
+func githubClient() *github.Client {
+	t := githubToken()
+	if t == "" {
+		return github.NewClient(&http.Client{Transport: transport})
+	}
+	return github.NewClient(&http.Client{Transport: &tokenTransport{token: t, base: transport}})
+}
+
+// otherSourceExts are the extensions of the non-Go source files the go tool
+// builds into a package, in assembly or through cgo.
+var otherSourceExts = map[string]bool{
+	".s": true, ".S": true, ".c": true, ".h": true, ".cc": true, ".cpp": true,
+	".cxx": true, ".hh": true, ".hpp": true, ".m": true, ".f": true, ".syso": true,
+}
+
+// countOtherFile counts name under its extension if it is a non-Go source
+// file.
+func countOtherFile(counts map[string]int, name string) {
+	if ext := path.Ext(name); otherSourceExts[ext] {
+		counts[ext]++
+	}
+}
+
+// analysePackages groups files by the package they declare and analyses each
+// package in turn. partial marks results as missing files.
+func analysePackages(path string, fset *token.FileSet, files []*ast.File, partial bool) []analyser.Result {
+	byName := map[string][]*ast.File{}
+	for _, f := range files {
+		byName[f.Name.Name] = append(byName[f.Name.Name], f)
+	}
+
+	names := alphabetical{}
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Sort(names)
+
+	out := []analyser.Result{}
+	for _, name := range names {
+		r := analyse(path, fset, byName[name])
+		r.Partial = partial
+		out = append(out, r)
+	}
+	return out
+}
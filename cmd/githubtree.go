@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// githubDir is a directory of a GitHub repository holding Go files.
+type githubDir struct {
+	dir string
+	// goFiles maps the path of each Go file to the SHA of its blob.
+	goFiles    map[string]string
+	otherFiles map[string]int
+}
+
+// parseGithubModule analyses every package beneath a location. The files are
+// listed with a single call to the Git Trees API, falling back to listing
+// each directory if the tree is too large to be returned whole.
+func parseGithubModule(pkg string, loc githubLocation) (target, error) {
+	client := githubClient()
+	ctx := context.Background()
+
+	rev := loc.ref
+	if rev == "" {
+		rev = "HEAD"
+	}
+	stop := startPhase(phaseFetch)
+	tree, _, err := client.Git.GetTree(ctx, loc.owner, loc.repo, rev, true)
+	stop()
+	if err != nil {
+		return target{}, fmt.Errorf("getting tree: %w", err)
+	}
+
+	dirs := map[string]*githubDir{}
+	add := func(p, sha string) {
+		dir := path.Dir(p)
+		if !githubDirIncluded(loc.path, dir) {
+			return
+		}
+		d, ok := dirs[dir]
+		if !ok {
+			d = &githubDir{dir: dir, goFiles: map[string]string{}, otherFiles: map[string]int{}}
+			dirs[dir] = d
+		}
+		if strings.HasSuffix(p, ".go") {
+			d.goFiles[p] = sha
+		} else {
+			countOtherFile(d.otherFiles, path.Base(p))
+		}
+	}
+
+	if tree.GetTruncated() {
+		if err := listGithubDirs(ctx, client, loc, loc.path, add); err != nil {
+			return target{}, err
+		}
+	} else {
+		for _, e := range tree.Entries {
+			if e.GetType() == "blob" {
+				add(e.GetPath(), e.GetSHA())
+			}
+		}
+	}
+
+	t := target{root: loc.path, module: &moduleSummary{}}
+	names := []string{}
+	for dir, d := range dirs {
+		if len(d.goFiles) == 0 {
+			continue
+		}
+		// the import path is assumed to follow the repository's
+		if excludedPackage(path.Join("github.com", loc.owner, loc.repo, dir)) {
+			t.module.excluded++
+			continue
+		}
+		names = append(names, dir)
+	}
+	if len(names) == 0 {
+		return target{}, fmt.Errorf("no go files found in %s", pkg)
+	}
+	sort.Sort(alphabetical(names))
+
+	for _, name := range names {
+		results, err := analyseGithubDir(ctx, client, loc, dirs[name])
+		if err != nil {
+			return target{}, err
+		}
+		t.results = append(t.results, results...)
+
+		rel := strings.TrimPrefix(strings.TrimPrefix(name, loc.path), "/")
+		if rel == "" {
+			rel = "."
+		}
+		t.module.packages = append(t.module.packages, rel)
+	}
+	return t, nil
+}
+
+// githubDirIncluded reports whether dir is root or beneath it, and isn't
+// skipped when recursing.
+func githubDirIncluded(root, dir string) bool {
+	if dir == "." {
+		dir = ""
+	}
+	if root != "" {
+		if dir != root && !strings.HasPrefix(dir, root+"/") {
+			return false
+		}
+		dir = strings.TrimPrefix(strings.TrimPrefix(dir, root), "/")
+	}
+	if dir == "" {
+		return true
+	}
+	for _, seg := range strings.Split(dir, "/") {
+		if skipDir(seg) {
+			return false
+		}
+	}
+	return true
+}
+
+// listGithubDirs lists the files beneath dir one directory at a time, for
+// repositories too large for a recursive tree.
+func listGithubDirs(ctx context.Context, client *github.Client, loc githubLocation, dir string, add func(path, sha string)) error {
+	stop := startPhase(phaseFetch)
+	_, entries, _, err := client.Repositories.GetContents(ctx, loc.owner, loc.repo, dir, &github.RepositoryContentGetOptions{Ref: loc.ref})
+	stop()
+	if err != nil {
+		return fmt.Errorf("getting %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		switch e.GetType() {
+		case "file":
+			add(e.GetPath(), e.GetSHA())
+		case "dir":
+			if skipDir(e.GetName()) {
+				continue
+			}
+			if err := listGithubDirs(ctx, client, loc, e.GetPath(), add); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// analyseGithubDir fetches the blobs of the Go files of a directory and
+// analyses the packages they declare.
+func analyseGithubDir(ctx context.Context, client *github.Client, loc githubLocation, d *githubDir) ([]analyser.Result, error) {
+	names := []string{}
+	for name := range d.goFiles {
+		names = append(names, name)
+	}
+	sort.Sort(alphabetical(names))
+	total := len(names)
+	sampled := []string{}
+	for _, i := range sampleIndices(total) {
+		sampled = append(sampled, names[i])
+	}
+	names = sampled
+
+	partial := false
+	if maxFiles > 0 && len(names) > maxFiles {
+		names, partial = names[:maxFiles], true
+	}
+
+	fset := token.NewFileSet() // positions are relative to fset
+	files := []*ast.File{}
+	for _, name := range names {
+		stop := startPhase(phaseFetch)
+		b, _, err := client.Git.GetBlobRaw(ctx, loc.owner, loc.repo, d.goFiles[name])
+		stop()
+		if err != nil {
+			return nil, fmt.Errorf("getting %s: %w", name, err)
+		}
+
+		stop = startPhase(phaseParse)
+		fp, err := parser.ParseFile(fset, name, b, parser.ParseComments)
+		stop()
+		if err != nil {
+			return nil, fmt.Errorf("parsing file: %w", err)
+		}
+		files = append(files, fp)
+	}
+
+	results := analysePackages(path.Join("github.com", loc.owner, loc.repo, d.dir), fset, files, partial)
+	for i := range results {
+		results[i].OtherFiles = d.otherFiles
+		if len(sampled) < total {
+			results[i].SampledFrom = total
+		}
+	}
+	if showActivity {
+		if err := addActivity(ctx, client, loc, results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// gitRevision reads files from the commit of a git repository, leaving the
+// working tree alone.
+type gitRevision struct {
+	ref  string
+	base string // root of the working tree
+	tree *object.Tree
+}
+
+// openGitRevision resolves ref in the git repository containing dir.
+func openGitRevision(dir, ref string) (gitRevision, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return gitRevision{}, fmt.Errorf("opening git repository: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return gitRevision{}, fmt.Errorf("opening git worktree: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return gitRevision{}, fmt.Errorf("resolving revision %q: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return gitRevision{}, fmt.Errorf("reading commit %s: %w", hash, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return gitRevision{}, fmt.Errorf("reading tree of %s: %w", hash, err)
+	}
+
+	base, err := filepath.EvalSymlinks(wt.Filesystem.Root())
+	if err != nil {
+		return gitRevision{}, err
+	}
+	return gitRevision{ref: ref, base: base, tree: tree}, nil
+}
+
+// subtree returns the tree of dir at the revision.
+func (g gitRevision) subtree(dir string) (*object.Tree, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(g.base, resolveExisting(abs))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("%s is outside the git repository at %s", dir, g.base)
+	}
+	if rel == "." {
+		return g.tree, nil
+	}
+
+	t, err := g.tree.Tree(filepath.ToSlash(rel))
+	if err != nil {
+		return nil, fmt.Errorf("%s not found at %s: %w", dir, g.ref, err)
+	}
+	return t, nil
+}
+
+// resolveExisting evaluates the symlinks of the longest part of the absolute
+// path abs that exists, joining what's left lexically: a package deleted or
+// moved since the revision has no directory left to resolve.
+func resolveExisting(abs string) string {
+	rest := ""
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if filepath.Dir(dir) == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+	}
+}
+
+func (g gitRevision) packageDirs(root string) ([]string, error) {
+	t, err := g.subtree(root)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{}
+	var walk func(dir string, t *object.Tree) error
+	walk = func(dir string, tree *object.Tree) error {
+		for _, e := range tree.Entries {
+			if e.Mode.IsFile() && strings.HasSuffix(e.Name, ".go") {
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+		for _, e := range tree.Entries {
+			if e.Mode != filemode.Dir || skipDir(e.Name) {
+				continue
+			}
+			sub, err := tree.Tree(e.Name)
+			if err != nil {
+				return fmt.Errorf("reading %s at %s: %w", filepath.Join(dir, e.Name), g.ref, err)
+			}
+			if err := walk(filepath.Join(dir, e.Name), sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root, t); err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+func (g gitRevision) parseDir(fset *token.FileSet, dir string) (map[string]*ast.Package, []string, error) {
+	t, err := g.subtree(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkgs := map[string]*ast.Package{}
+	names := []string{}
+	for _, e := range t.Entries {
+		if !e.Mode.IsFile() {
+			continue
+		}
+		names = append(names, e.Name)
+		if !strings.HasSuffix(e.Name, ".go") {
+			continue
+		}
+
+		f, err := t.File(e.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s at %s: %w", e.Name, g.ref, err)
+		}
+		src, err := f.Contents()
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s at %s: %w", e.Name, g.ref, err)
+		}
+		filename := filepath.Join(dir, e.Name)
+		fp, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pkg, ok := pkgs[fp.Name.Name]
+		if !ok {
+			pkg = &ast.Package{Name: fp.Name.Name, Files: map[string]*ast.File{}}
+			pkgs[fp.Name.Name] = pkg
+		}
+		pkg.Files[filename] = fp
+	}
+	return pkgs, names, nil
+}
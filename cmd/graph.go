@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"go/ast"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// importGraph maps the import path of each package to the packages it
+// imports.
+type importGraph map[string][]string
+
+// fileImports returns the import paths of files, which may repeat.
+func fileImports(files map[string]*ast.File) []string {
+	out := []string{}
+	for _, f := range files {
+		for _, i := range f.Imports {
+			if p, err := strconv.Unquote(i.Path.Value); err == nil {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// within returns the graph restricted to the packages of module modPath.
+// Self imports, which come from files such as generators sharing a directory
+// with the package they generate, are dropped.
+func (g importGraph) within(modPath string) importGraph {
+	in := func(p string) bool { return p == modPath || strings.HasPrefix(p, modPath+"/") }
+	out := importGraph{}
+	for pkg, imports := range g {
+		if !in(pkg) {
+			continue
+		}
+		out[pkg] = []string{}
+		for _, i := range imports {
+			if in(i) && i != pkg {
+				out[pkg] = append(out[pkg], i)
+			}
+		}
+	}
+	return out
+}
+
+// cycles finds the import cycles in g with a depth first search. Each cycle
+// is reported once, starting from its alphabetically first package and
+// ending where it started.
+func (g importGraph) cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	stack := []string{}
+	seen := map[string]bool{}
+	out := [][]string{}
+
+	var visit func(pkg string)
+	visit = func(pkg string) {
+		state[pkg] = visiting
+		stack = append(stack, pkg)
+		for _, next := range g[pkg] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						c := rotateCycle(stack[i:])
+						if key := strings.Join(c, " "); !seen[key] {
+							seen[key] = true
+							out = append(out, append(c, c[0]))
+						}
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[pkg] = done
+	}
+
+	pkgs := []string{}
+	for pkg := range g {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Sort(alphabetical(pkgs))
+	for _, pkg := range pkgs {
+		if state[pkg] == unvisited {
+			visit(pkg)
+		}
+	}
+	return out
+}
+
+// rotateCycle returns a copy of cycle starting at its smallest element.
+func rotateCycle(cycle []string) []string {
+	min := 0
+	for i := range cycle {
+		if cycle[i] < cycle[min] {
+			min = i
+		}
+	}
+	return append(append([]string{}, cycle[min:]...), cycle[:min]...)
+}
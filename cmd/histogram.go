@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aybabtme/uniplot/histogram"
+)
+
+const (
+	minHistWidth = 1
+	maxHistWidth = 200
+)
+
+// logScale scales bars by the logarithm of their count, so that a few very
+// large buckets don't flatten every other bar to nothing.
+func logScale(width int) histogram.ScaleFunc {
+	return func(min, max, value int) float64 {
+		if max <= 0 {
+			return 0
+		}
+		return math.Log1p(float64(value)) / math.Log1p(float64(max)) * float64(width)
+	}
+}
+
+// histScale returns the scale to draw histograms with, as set by flags.
+func histScale() (histogram.ScaleFunc, error) {
+	if histWidth < minHistWidth || histWidth > maxHistWidth {
+		return nil, fmt.Errorf("--hist-width must be between %d and %d, got %d", minHistWidth, maxHistWidth, histWidth)
+	}
+	switch histScaleName {
+	case "linear":
+		return histogram.Linear(histWidth), nil
+	case "log":
+		return logScale(histWidth), nil
+	}
+	return nil, fmt.Errorf("unknown --hist-scale %q, expected linear or log", histScaleName)
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws data as a single line, one block per histogram bucket with
+// its height proportional to the number of values in the bucket.
+func sparkline(data []float64, bins int) string {
+	h := histogram.Hist(bins, data)
+	if len(h.Buckets) == 0 {
+		return ""
+	}
+
+	out := []rune{}
+	for _, b := range h.Buckets {
+		i := 0
+		if h.Max > 0 {
+			i = b.Count * (len(sparks) - 1) / h.Max
+		}
+		out = append(out, sparks[i])
+	}
+	return fmt.Sprintf("%.4g %s %.4g", h.Buckets[0].Min, string(out), h.Buckets[len(h.Buckets)-1].Max)
+}
+
+// distribution summarises the values drawn in a histogram.
+type distribution struct {
+	mean, median, p90, max float64
+}
+
+// describe returns the mean, median, 90th percentile and maximum of data,
+// taking the nearest rank for the percentile.
+func describe(data []float64) distribution {
+	if len(data) == 0 {
+		return distribution{}
+	}
+	sorted := append([]float64{}, data...)
+	sort.Float64s(sorted)
+
+	var d distribution
+	for _, v := range sorted {
+		d.mean += v
+	}
+	d.mean /= float64(len(sorted))
+	mid := len(sorted) / 2
+	d.median = sorted[mid]
+	if len(sorted)%2 == 0 {
+		d.median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	d.p90 = sorted[int(math.Ceil(0.9*float64(len(sorted))))-1]
+	d.max = sorted[len(sorted)-1]
+	return d
+}
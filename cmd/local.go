@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// sourceTree lists and parses the package directories of a local tree.
+type sourceTree interface {
+	// packageDirs returns root and every directory beneath it holding Go
+	// files.
+	packageDirs(root string) ([]string, error)
+	// parseDir parses the Go files of dir, also returning the names of every
+	// file in it.
+	parseDir(fset *token.FileSet, dir string) (map[string]*ast.Package, []string, error)
+}
+
+// parseLocalPackage analyses a local package as of the git revision ref, or
+// if empty the working tree.
+func parseLocalPackage(root, ref string) (target, error) {
+	var src sourceTree = newWorktree(root)
+	if typedMode {
+		if ref != "" {
+			return target{}, fmt.Errorf("--typed can't be used with --ref")
+		}
+		src = newTypedTree(root)
+	}
+	if ref != "" {
+		var err error
+		if src, err = openGitRevision(root, ref); err != nil {
+			return target{}, err
+		}
+	}
+	return parseTree(src, root)
+}
+
+// parseTree analyses the package at root of a sourceTree, and with
+// --recursive every package beneath it.
+func parseTree(src sourceTree, root string) (target, error) {
+	t := target{root: root}
+	dirs := []string{root}
+	if recursive {
+		var err error
+		dirs, err = src.packageDirs(root)
+		if err != nil {
+			return t, err
+		}
+		t.module = &moduleSummary{}
+	}
+
+	mod, inModule := findModule(root)
+	if !inModule {
+		mod = module{root: root}
+	}
+	if t.module != nil && len(excludePackages) > 0 {
+		kept := []string{}
+		for _, dir := range dirs {
+			if excludedPackage(mod.importPath(dir)) {
+				t.module.excluded++
+				continue
+			}
+			kept = append(kept, dir)
+		}
+		dirs = kept
+	}
+
+	graph := importGraph{}
+	for _, dir := range dirs {
+		pkgs, err := cachedAnalyseDir(src, dir, mod, inModule)
+		if err != nil {
+			return t, err
+		}
+		for _, p := range pkgs {
+			t.results = append(t.results, p.Result)
+			if t.module == nil || !p.hasNonTest() {
+				continue
+			}
+			listed := t.module.packages
+			if len(listed) == 0 || listed[len(listed)-1] != mod.rel(dir) {
+				t.module.packages = append(listed, mod.rel(dir))
+			}
+			graph[p.Result.ImportPath] = append(graph[p.Result.ImportPath], p.Imports...)
+		}
+	}
+
+	if t.module != nil && inModule {
+		t.module.inModule = true
+		t.module.cycles = graph.within(mod.path).cycles()
+	}
+
+	return t, nil
+}
+
+// dirPackage is a package analysed from a directory, along with what a
+// recursive analysis records about it.
+type dirPackage struct {
+	Result analyser.Result `json:"result"`
+	// Names are the packages analysed together, and Imports those imported
+	// by the ones that aren't external tests.
+	Names   []string `json:"names"`
+	Imports []string `json:"imports"`
+}
+
+// hasNonTest reports whether any of the packages analysed together isn't an
+// external test package.
+func (p dirPackage) hasNonTest() bool {
+	for _, name := range p.Names {
+		if !strings.HasSuffix(name, "_test") {
+			return true
+		}
+	}
+	return false
+}
+
+// analyseDir parses and analyses the packages of a directory, reusing the
+// results held in c unless it's nil.
+func analyseDir(src sourceTree, dir string, mod module, inModule bool, c *resultCache) ([]dirPackage, error) {
+	fset := token.NewFileSet() // positions are relative to fset
+	stop := startPhase(phaseParse)
+	pkgs, fileNames, err := src.parseDir(fset, dir)
+	stop()
+	if err != nil {
+		return nil, err
+	}
+	otherFiles := map[string]int{}
+	for _, name := range fileNames {
+		countOtherFile(otherFiles, name)
+	}
+
+	out := []dirPackage{}
+	for _, group := range packageGroups(packageNames(pkgs)) {
+		merged := map[string]*ast.File{}
+		for _, name := range group {
+			for filename, f := range pkgs[name].Files {
+				merged[filename] = f
+			}
+		}
+		files := sortedFiles(merged)
+		sampled := []*ast.File{}
+		for _, i := range sampleIndices(len(files)) {
+			sampled = append(sampled, files[i])
+		}
+		var typed *typedPackage
+		if tt, ok := src.(*typedTree); ok && len(group) == 1 {
+			typed = tt.packages[dir][group[0]]
+		}
+		var r analyser.Result
+		if c != nil {
+			r = analyseCached(dir, fset, sampled, c)
+		} else {
+			r = analyseTyped(dir, fset, sampled, typed)
+		}
+		r.Name = group[0]
+		if len(sampled) < len(files) {
+			r.SampledFrom = len(files)
+		}
+		if inModule {
+			r.ImportPath = mod.importPath(dir)
+		}
+		r.OtherFiles = otherFiles
+
+		p := dirPackage{Result: r, Names: group, Imports: []string{}}
+		for _, name := range group {
+			if !strings.HasSuffix(name, "_test") {
+				p.Imports = append(p.Imports, fileImports(pkgs[name].Files)...)
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// skipDir reports whether a directory is left out when recursing: those the
+// go tool ignores and, unless --include-vendor is set, vendored dependencies.
+func skipDir(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" {
+		return true
+	}
+	return name == "vendor" && !includeVendor
+}
+
+// packageGroups returns the packages of a directory that are analysed
+// together. Each is analysed alone unless --merge-packages is set, when all
+// of them, sharing the directory's import path, are merged into one named
+// after the first that isn't an external test package.
+func packageGroups(names []string) [][]string {
+	if !mergePackages || len(names) < 2 {
+		groups := [][]string{}
+		for _, name := range names {
+			groups = append(groups, []string{name})
+		}
+		return groups
+	}
+
+	group := []string{}
+	for _, name := range names {
+		if !strings.HasSuffix(name, "_test") && len(group) == 0 {
+			group = append(group, name)
+		}
+	}
+	for _, name := range names {
+		if len(group) == 0 || name != group[0] {
+			group = append(group, name)
+		}
+	}
+	return [][]string{group}
+}
+
+// packageNames returns the names of parsed packages in a stable order.
+func packageNames(pkgs map[string]*ast.Package) []string {
+	names := []string{}
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Sort(alphabetical(names))
+	return names
+}
+
+// sortedFiles returns the files of a parsed package ordered by filename, so
+// that output doesn't depend on map iteration order.
+func sortedFiles(files map[string]*ast.File) []*ast.File {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Sort(alphabetical(names))
+
+	out := []*ast.File{}
+	for _, name := range names {
+		out = append(out, files[name])
+	}
+	return out
+}
+
+type alphabetical []string
+
+func (a alphabetical) Len() int           { return len(a) }
+func (a alphabetical) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a alphabetical) Less(i, j int) bool { return a[i] < a[j] }
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// manifest is the exported API of the packages found for an argument, keyed
+// by package name.
+type manifest struct {
+	Packages map[string][]analyser.APISymbol `json:"packages"`
+}
+
+var (
+	manifestFile string
+	headSymbols  int
+	tailSymbols  int
+)
+
+// symbolsCmd lists the exported API of a package
+var symbolsCmd = &cobra.Command{
+	Use:   "symbols <package>",
+	Short: "Lists the exported symbols of a package, or writes them to a manifest for check",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if headSymbols > 0 && tailSymbols > 0 {
+			return fmt.Errorf("--head and --tail can't be used together")
+		}
+		m, err := packageManifest(args[0])
+		if err != nil {
+			return err
+		}
+		if manifestFile != "" {
+			b, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding manifest: %w", err)
+			}
+			if err := os.WriteFile(manifestFile, append(b, '\n'), 0o644); err != nil {
+				return fmt.Errorf("writing manifest: %w", err)
+			}
+			fmt.Printf("Wrote %s\n", manifestFile)
+			return nil
+		}
+
+		names := map[string]bool{}
+		for name := range m.Packages {
+			names[name] = true
+		}
+		for _, name := range sortedKeys(names) {
+			fmt.Printf("package %s\n", name)
+			symbols := m.Packages[name]
+			omitted := 0
+			switch {
+			case headSymbols > 0 && len(symbols) > headSymbols:
+				symbols, omitted = symbols[:headSymbols], len(symbols)-headSymbols
+			case tailSymbols > 0 && len(symbols) > tailSymbols:
+				omitted = len(symbols) - tailSymbols
+				symbols = symbols[omitted:]
+				fmt.Printf("  ... %d more symbol(s) before\n", omitted)
+			}
+			for _, s := range symbols {
+				fmt.Printf("  %s %s %s\n", s.Kind, s.Name, s.Signature)
+			}
+			if headSymbols > 0 && omitted > 0 {
+				fmt.Printf("  ... %d more symbol(s)\n", omitted)
+			}
+		}
+		return nil
+	},
+}
+
+// checkCmd compares the exported API of a package with a manifest
+var checkCmd = &cobra.Command{
+	Use:   "check <package>",
+	Short: "Fails if the exported symbols of a package differ from a manifest written by symbols --manifest",
+	Args:  cobra.ExactArgs(1),
+	// a differing API is an expected failure, not a misuse
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := os.ReadFile(manifestFile)
+		if err != nil {
+			return fmt.Errorf("reading manifest: %w", err)
+		}
+		want := manifest{}
+		if err := json.Unmarshal(b, &want); err != nil {
+			return fmt.Errorf("decoding manifest: %w", err)
+		}
+		got, err := packageManifest(args[0])
+		if err != nil {
+			return err
+		}
+
+		diffs := diffManifests(want, got)
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+		if len(diffs) > 0 {
+			return fmt.Errorf("%d change(s) to the API of %s", len(diffs), args[0])
+		}
+		fmt.Printf("The API of %s matches %s\n", args[0], manifestFile)
+		return nil
+	},
+}
+
+func init() {
+	symbolsCmd.Flags().StringVar(&manifestFile, "manifest", "", "Write the symbols to this file as a manifest")
+	symbolsCmd.Flags().IntVar(&headSymbols, "head", 0, "Only print the first this many symbols of each package")
+	symbolsCmd.Flags().IntVar(&tailSymbols, "tail", 0, "Only print the last this many symbols of each package")
+	checkCmd.Flags().StringVar(&manifestFile, "manifest", "", "Manifest to check the package against")
+	cobra.CheckErr(checkCmd.MarkFlagRequired("manifest"))
+	rootCmd.AddCommand(symbolsCmd, checkCmd)
+}
+
+// packageManifest analyses pkg and gathers the API of each package found.
+func packageManifest(pkg string) (manifest, error) {
+	t, err := run(pkg, "")
+	if err != nil {
+		return manifest{}, err
+	}
+	return targetManifest(t), nil
+}
+
+// targetManifest gathers the API of each package of an analysed target.
+func targetManifest(t target) manifest {
+	m := manifest{Packages: map[string][]analyser.APISymbol{}}
+	for _, r := range t.results {
+		symbols, _ := r.Metrics[analyser.APIMetric].([]analyser.APISymbol)
+		m.Packages[r.Name] = symbols
+	}
+	return m
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := []string{}
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Sort(alphabetical(out))
+	return out
+}
+
+// diffManifests describes every symbol added, removed or changed in got
+// compared with want, as lines prefixed with +, - or ~.
+func diffManifests(want, got manifest) []string {
+	out := []string{}
+	names := map[string]bool{}
+	for name := range want.Packages {
+		names[name] = true
+	}
+	for name := range got.Packages {
+		names[name] = true
+	}
+
+	for _, pkg := range sortedKeys(names) {
+		before := map[string]analyser.APISymbol{}
+		for _, s := range want.Packages[pkg] {
+			before[s.Name] = s
+		}
+		after := map[string]analyser.APISymbol{}
+		for _, s := range got.Packages[pkg] {
+			after[s.Name] = s
+		}
+
+		symbols := map[string]bool{}
+		for name := range before {
+			symbols[name] = true
+		}
+		for name := range after {
+			symbols[name] = true
+		}
+		for _, name := range sortedKeys(symbols) {
+			b, hadBefore := before[name]
+			a, hasAfter := after[name]
+			switch {
+			case !hadBefore:
+				out = append(out, fmt.Sprintf("+ %s: %s %s %s", pkg, a.Kind, a.Name, a.Signature))
+			case !hasAfter:
+				out = append(out, fmt.Sprintf("- %s: %s %s %s", pkg, b.Kind, b.Name, b.Signature))
+			case a != b:
+				out = append(out, fmt.Sprintf("~ %s: %s %s %s, was %s %s", pkg, a.Kind, a.Name, a.Signature, b.Kind, b.Signature))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// writeMermaid writes the types of a package as a Mermaid class diagram,
+// fenced for pasting into Markdown. Interfaces are linked to the types
+// implementing them and types to the local types they embed: structs by
+// composition and interfaces by inheritance.
+func writeMermaid(w io.Writer, r analyser.Result) error {
+	types, _ := r.Metrics[analyser.TypesMetric].([]analyser.Type)
+	impls, _ := r.Metrics[analyser.ImplementationsMetric].([]analyser.Implementation)
+
+	local := map[string]bool{}
+	for _, t := range types {
+		local[t.Name] = true
+	}
+
+	lines := []string{"```mermaid", "classDiagram", fmt.Sprintf("    %%%% package %s", r.Name)}
+	for _, t := range types {
+		lines = append(lines, fmt.Sprintf("    class %s {", t.Name))
+		if t.Kind == "interface" {
+			lines = append(lines, "        <<interface>>")
+		}
+		for _, m := range t.Methods {
+			lines = append(lines, fmt.Sprintf("        +%s()", m))
+		}
+		lines = append(lines, "    }")
+	}
+	for _, t := range types {
+		for _, e := range t.Embeds {
+			// generic instantiations are linked to their type
+			if i := strings.Index(e, "["); i >= 0 {
+				e = e[:i]
+			}
+			if !local[e] {
+				continue
+			}
+			arrow := "*--"
+			if t.Kind == "interface" {
+				arrow = "<|--"
+			}
+			lines = append(lines, fmt.Sprintf("    %s %s %s : embeds", e, arrow, t.Name))
+		}
+	}
+	for _, i := range impls {
+		label := "implements"
+		if i.Pointer {
+			label = "implements as pointer"
+		}
+		lines = append(lines, fmt.Sprintf("    %s <|.. %s : %s", i.Interface, i.Type, label))
+	}
+	lines = append(lines, "```")
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// metricsCmd lists the metrics that can be computed
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Lists every metric computed for a package along with what it measures",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jsonOut || jsonPretty {
+			enc := json.NewEncoder(os.Stdout)
+			if jsonPretty {
+				enc.SetIndent("", "  ")
+			}
+			return enc.Encode(metricDefinitions())
+		}
+		printMetricDefinitions(os.Stdout, metricDefinitions())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+}
+
+// metricDefinition describes a registered metric.
+type metricDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Enabled is whether the metric is computed by default. Every
+	// registered metric currently is.
+	Enabled bool `json:"enabled"`
+	// Typed is set for metrics made more precise by --typed.
+	Typed bool `json:"typed"`
+}
+
+// metricDefinitions describes every registered metric in the order they
+// were registered.
+func metricDefinitions() []metricDefinition {
+	out := []metricDefinition{}
+	for _, m := range analyser.Metrics() {
+		_, typed := m.(analyser.TypedMetric)
+		out = append(out, metricDefinition{
+			Name:        m.Name(),
+			Description: analyser.Describe(m.Name()),
+			Enabled:     true,
+			Typed:       typed,
+		})
+	}
+	return out
+}
+
+func printMetricDefinitions(w io.Writer, defs []metricDefinition) {
+	for _, d := range defs {
+		name := d.Name
+		if d.Typed {
+			name += " (more precise with --typed)"
+		}
+		fmt.Fprintln(w, name)
+		if d.Description != "" {
+			fmt.Fprintf(w, "    %s\n", d.Description)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// module is the Go module a local directory belongs to.
+type module struct {
+	root string
+	path string
+}
+
+// findModule looks for the go.mod governing dir, searching upwards.
+func findModule(dir string) (module, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return module{}, false
+	}
+	for {
+		if p, ok := modulePath(filepath.Join(abs, "go.mod")); ok {
+			return module{root: abs, path: p}, true
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return module{}, false
+		}
+		abs = parent
+	}
+}
+
+// modulePath reads the module path declared in a go.mod file.
+func modulePath(gomod string) (string, bool) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+		p := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		if unquoted, err := strconv.Unquote(p); err == nil {
+			p = unquoted
+		}
+		return p, p != ""
+	}
+	return "", false
+}
+
+// rel returns dir relative to the module root, using forward slashes.
+func (m module) rel(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	r, err := filepath.Rel(m.root, abs)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	return filepath.ToSlash(r)
+}
+
+// importPath returns the import path of the package in dir.
+func (m module) importPath(dir string) string {
+	return path.Join(m.path, m.rel(dir))
+}
+
+// excludedPackage reports whether the package with importPath matches one of
+// --exclude-package's patterns. Patterns are path.Match globs, and like the
+// go tool's, one ending in /... also matches every package beneath it.
+func excludedPackage(importPath string) bool {
+	for _, pattern := range excludePackages {
+		p := importPath
+		prefix := strings.TrimSuffix(pattern, "/...")
+		for {
+			if ok, _ := path.Match(prefix, p); ok {
+				return true
+			}
+			if prefix == pattern || !strings.Contains(p, "/") {
+				break
+			}
+			p = path.Dir(p)
+		}
+	}
+	return false
+}
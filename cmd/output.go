@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// outputTarget outputs the results of a package argument, followed by a
+// summary of the module when it was analysed recursively.
+func outputTarget(t target) error {
+	displayRoot = t.root
+	for _, r := range t.results {
+		if err := output(r); err != nil {
+			return err
+		}
+	}
+
+	if t.module == nil || metricsNDJSON || jsonOut || jsonPretty || noSummary || mermaid || dotCallGraph {
+		// summaries would break the one record per line
+		return nil
+	}
+	fmt.Printf("Module contains %d package(s): %q\n", len(t.module.packages), t.module.packages)
+	if t.module.excluded > 0 {
+		fmt.Printf("Skipped %d package(s) matching --exclude-package\n", t.module.excluded)
+	}
+	if t.module.inModule {
+		if len(t.module.cycles) == 0 {
+			fmt.Println("No import cycles between the module's packages")
+		}
+		for _, c := range t.module.cycles {
+			fmt.Printf("Import cycle: %s\n", strings.Join(c, " -> "))
+		}
+	}
+	return nil
+}
+
+// printRunSummary totals the files, exported functions and distinct imports
+// of every package analysed, when there was more than one.
+func printRunSummary(w io.Writer, targets []target) {
+	if jsonOut || jsonPretty || noSummary || mermaid || dotCallGraph {
+		return
+	}
+	packages, files, exported := 0, 0, 0
+	imports := map[string]bool{}
+	for _, t := range targets {
+		for _, r := range t.results {
+			packages++
+			files += r.Files
+			e, _ := r.Metrics[analyser.ExportedFuncsMetric].(analyser.ExportedFuncs)
+			exported += e.Total
+			is, _ := r.Metrics[analyser.ImportsMetric].([]string)
+			for _, i := range is {
+				imports[i] = true
+			}
+		}
+	}
+	if packages < 2 {
+		return
+	}
+	fmt.Fprintf(w, "In total, %d package(s) have %d file(s), %d exported function(s) and %d unique import(s)\n", packages, files, exported, len(imports))
+}
+
+// undocumented counts the exported symbols without a doc comment across
+// every package analysed.
+func undocumented(targets []target) int {
+	n := 0
+	for _, t := range targets {
+		for _, r := range t.results {
+			d, _ := r.Metrics[analyser.DocsMetric].(analyser.DocCoverage)
+			n += len(d.Undocumented)
+		}
+	}
+	return n
+}
+
+// output sends the result of a package to wherever the flags ask for it.
+func output(r analyser.Result) error {
+	if outputDir != "" {
+		return writeResultFile(outputDir, r)
+	}
+	if metricsNDJSON {
+		return writeMetricRecords(os.Stdout, r, time.Now().UTC())
+	}
+	if jsonOut || jsonPretty {
+		return writeJSON(os.Stdout, r, jsonPretty)
+	}
+	if mermaid {
+		return writeMermaid(os.Stdout, r)
+	}
+	if dotCallGraph {
+		return writeCallGraph(os.Stdout, r)
+	}
+	return printResult(os.Stdout, r)
+}
+
+// metricRecord is a single metric of a package, written as a line of NDJSON
+// for time series databases and log aggregators to ingest.
+type metricRecord struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Package   string      `json:"package"`
+	Metric    string      `json:"metric"`
+	Value     interface{} `json:"value"`
+}
+
+// writeMetricRecords writes a metricRecord per metric of r, identifying the
+// package by its import path where known.
+func writeMetricRecords(w io.Writer, r analyser.Result, at time.Time) error {
+	pkg := r.ImportPath
+	if pkg == "" {
+		pkg = r.Path
+	}
+	enc := json.NewEncoder(w)
+	for _, name := range analyser.Names() {
+		if err := enc.Encode(metricRecord{Timestamp: at, Package: pkg, Metric: name, Value: r.Metrics[name]}); err != nil {
+			return fmt.Errorf("encoding %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// writeJSON writes r as JSON, compact on a single line or, if indent is set,
+// indented for reading. Either way it has the same schema as the files
+// written by --output-dir.
+func writeJSON(w io.Writer, r analyser.Result, indent bool) error {
+	enc := json.NewEncoder(w)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("encoding result: %w", err)
+	}
+	return nil
+}
+
+// writeResultFile writes r as JSON into dir, creating dir if needed.
+func writeResultFile(dir string, r analyser.Result) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating output dir: %w", err)
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding result: %w", err)
+	}
+
+	name := filepath.Join(dir, resultFilename(r))
+	if err := os.WriteFile(name, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing result: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", name)
+	return nil
+}
+
+// resultFilename names a result's file after its package, qualified by the
+// path it was found at so that packages from different places don't clash.
+func resultFilename(r analyser.Result) string {
+	key := r.Name
+	if p := strings.Trim(filepath.ToSlash(r.Path), "./"); p != "" {
+		key = p
+		if path.Base(p) != r.Name {
+			key = p + "/" + r.Name
+		}
+	}
+	return safeFilename(key) + ".json"
+}
+
+// safeFilename replaces anything that isn't safe to use in a filename.
+func safeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/spf13/cobra"
+)
+
+// prCmd analyses the Go files changed by a pull request
+var prCmd = &cobra.Command{
+	Use:   "pr github.com/owner/repo/pull/123",
+	Short: "Analyses the Go files changed by a GitHub pull request",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := histScale(); err != nil {
+			return err
+		}
+		t, err := parsePullRequest(args[0])
+		if err != nil {
+			return err
+		}
+		return outputTarget(t)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+}
+
+// pullRequest identifies a pull request on GitHub.
+type pullRequest struct {
+	owner, repo string
+	number      int
+}
+
+// parsePullRequestURL splits a URL such as github.com/owner/repo/pull/123
+// into its parts.
+func parsePullRequestURL(pr string) (pullRequest, error) {
+	loc, err := parseGithubLocation(pr)
+	if err != nil {
+		return pullRequest{}, err
+	}
+	s := strings.Split(loc.path, "/")
+	if len(s) < 2 || s[0] != "pull" {
+		return pullRequest{}, fmt.Errorf("pull request not specified in %s", pr)
+	}
+	n, err := strconv.Atoi(s[1])
+	if err != nil {
+		return pullRequest{}, fmt.Errorf("invalid pull request number %q in %s", s[1], pr)
+	}
+	return pullRequest{owner: loc.owner, repo: loc.repo, number: n}, nil
+}
+
+// parsePullRequest analyses the Go files a pull request adds or modifies, as
+// they are at the head of the pull request, one package per directory.
+func parsePullRequest(pr string) (target, error) {
+	p, err := parsePullRequestURL(pr)
+	if err != nil {
+		return target{}, err
+	}
+
+	client := githubClient()
+	ctx := context.Background()
+	stop := startPhase(phaseFetch)
+	pull, _, err := client.PullRequests.Get(ctx, p.owner, p.repo, p.number)
+	if err != nil {
+		return target{}, fmt.Errorf("getting pull request: %w", err)
+	}
+	ref := &github.RepositoryContentGetOptions{Ref: pull.GetHead().GetSHA()}
+
+	changed := []string{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := client.PullRequests.ListFiles(ctx, p.owner, p.repo, p.number, opts)
+		if err != nil {
+			return target{}, fmt.Errorf("listing pull request files: %w", err)
+		}
+		for _, f := range files {
+			if strings.HasSuffix(f.GetFilename(), ".go") && f.GetStatus() != "removed" {
+				changed = append(changed, f.GetFilename())
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	stop()
+	if len(changed) == 0 {
+		return target{}, fmt.Errorf("no go files changed in %s", pr)
+	}
+	sort.Sort(alphabetical(changed))
+
+	partial := false
+	if maxFiles > 0 && len(changed) > maxFiles {
+		changed, partial = changed[:maxFiles], true
+	}
+
+	fset := token.NewFileSet() // positions are relative to fset
+	byDir := map[string][]*ast.File{}
+	dirs := []string{}
+	for _, name := range changed {
+		stop := startPhase(phaseFetch)
+		fileC, _, _, err := client.Repositories.GetContents(ctx, p.owner, p.repo, name, ref)
+		stop()
+		if err != nil {
+			return target{}, fmt.Errorf("getting file: %w", err)
+		}
+		c, err := fileC.GetContent()
+		if err != nil {
+			return target{}, fmt.Errorf("getting file contents: %w", err)
+		}
+
+		stop = startPhase(phaseParse)
+		fp, err := parser.ParseFile(fset, name, c, parser.ParseComments)
+		stop()
+		if err != nil {
+			return target{}, fmt.Errorf("parsing file: %w", err)
+		}
+		dir := path.Dir(name)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], fp)
+	}
+
+	t := target{}
+	for _, dir := range dirs {
+		pkg := path.Join("github.com", p.owner, p.repo, dir)
+		t.results = append(t.results, analysePackages(pkg, fset, byDir[dir], partial)...)
+	}
+	return t, nil
+}
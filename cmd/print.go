@@ -0,0 +1,571 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aybabtme/uniplot/histogram"
+	"github.com/trelore/package-analyser/analyser"
+	"github.com/trelore/package-analyser/baseline"
+)
+
+// printed are the metrics printResult knows how to present. Any other
+// registered metric is printed as is, unless it reports Warnings.
+var printed = map[string]bool{
+	analyser.ExportedFuncsMetric:   true,
+	analyser.ImportsMetric:         true,
+	analyser.FileLinesMetric:       true,
+	analyser.PlatformsMetric:       true,
+	analyser.RiskyPackagesMetric:   true,
+	analyser.UnreferencedMetric:    true,
+	analyser.TypesMetric:           true,
+	analyser.ShadowMetric:          true,
+	analyser.BareReceivesMetric:    true,
+	analyser.ComplexityMetric:      true,
+	analyser.HalsteadMetric:        true,
+	analyser.MaintainabilityMetric: true,
+	analyser.FanOutMetric:          true,
+	analyser.StubsMetric:           true,
+	analyser.MultipleReturnsMetric: true,
+	analyser.EmptyInterfaceMetric:  true,
+	analyser.DocsMetric:            true,
+	analyser.MethodNamesMetric:     true,
+	analyser.ConstructorsMetric:    true,
+	analyser.APIMetric:             true,
+	analyser.ControlFlowMetric:     true,
+	analyser.BuildTagsMetric:       true,
+	analyser.MutableMetric:         true,
+	analyser.ImplementationsMetric: true,
+	analyser.VariadicMetric:        true,
+	analyser.ImportsPerFileMetric:  true,
+	analyser.StructTagsMetric:      true,
+	analyser.OptionsMetric:         true,
+	analyser.SignaturesMetric:      true,
+	analyser.GenericsMetric:        true,
+	analyser.SecretsMetric:         true,
+	analyser.ErrorWrappingMetric:   true,
+	analyser.ReceiversMetric:       true,
+	analyser.CallGraphMetric:       true,
+	analyser.FuncLinesMetric:       true,
+	analyser.BlankAssignsMetric:    true,
+	analyser.SliceParamsMetric:     true,
+	analyser.NameVerbsMetric:       true,
+	analyser.LargeParamsMetric:     true,
+}
+
+func printResult(w io.Writer, r analyser.Result) error {
+	exported, _ := r.Metrics[analyser.ExportedFuncsMetric].(analyser.ExportedFuncs)
+	imports, _ := r.Metrics[analyser.ImportsMetric].([]string)
+	lines, _ := r.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+	platforms, _ := r.Metrics[analyser.PlatformsMetric].(map[string]int)
+	risky, _ := r.Metrics[analyser.RiskyPackagesMetric].(map[string]analyser.PackageUsage)
+	unreferenced, _ := r.Metrics[analyser.UnreferencedMetric].([]string)
+	types, _ := r.Metrics[analyser.TypesMetric].([]analyser.Type)
+	complexity, _ := r.Metrics[analyser.ComplexityMetric].([]analyser.FuncComplexity)
+	halstead, _ := r.Metrics[analyser.HalsteadMetric].(analyser.Halstead)
+	mi, _ := r.Metrics[analyser.MaintainabilityMetric].(analyser.Maintainability)
+	fanOut, _ := r.Metrics[analyser.FanOutMetric].(analyser.FanOut)
+	multi, _ := r.Metrics[analyser.MultipleReturnsMetric].(analyser.MultipleReturns)
+	emptyIfaces, _ := r.Metrics[analyser.EmptyInterfaceMetric].([]analyser.FuncPos)
+	docs, _ := r.Metrics[analyser.DocsMetric].(analyser.DocCoverage)
+	methodNames, _ := r.Metrics[analyser.MethodNamesMetric].([]analyser.MethodName)
+	constructors, _ := r.Metrics[analyser.ConstructorsMetric].([]analyser.Constructor)
+	flow, _ := r.Metrics[analyser.ControlFlowMetric].(analyser.ControlFlow)
+	tags, _ := r.Metrics[analyser.BuildTagsMetric].(map[string]int)
+	mutable, _ := r.Metrics[analyser.MutableMetric].([]analyser.MutableSite)
+	impls, _ := r.Metrics[analyser.ImplementationsMetric].([]analyser.Implementation)
+	variadic, _ := r.Metrics[analyser.VariadicMetric].([]analyser.VariadicFunc)
+	fileImports, _ := r.Metrics[analyser.ImportsPerFileMetric].([]analyser.FileImports)
+	structTags, _ := r.Metrics[analyser.StructTagsMetric].(map[string]int)
+	options, _ := r.Metrics[analyser.OptionsMetric].([]analyser.OptionsFunc)
+	signatures, _ := r.Metrics[analyser.SignaturesMetric].([]analyser.Signature)
+	generic, _ := r.Metrics[analyser.GenericsMetric].([]analyser.Symbol)
+	secrets, _ := r.Metrics[analyser.SecretsMetric].([]analyser.Secret)
+	wrapping, _ := r.Metrics[analyser.ErrorWrappingMetric].(analyser.ErrorWrapping)
+	receivers, _ := r.Metrics[analyser.ReceiversMetric].([]analyser.ReceiverKinds)
+	funcLines, _ := r.Metrics[analyser.FuncLinesMetric].([]analyser.FuncLength)
+	blanks, _ := r.Metrics[analyser.BlankAssignsMetric].([]analyser.FuncPos)
+	slices, _ := r.Metrics[analyser.SliceParamsMetric].(analyser.SliceParams)
+	verbs, _ := r.Metrics[analyser.NameVerbsMetric].([]analyser.VerbCount)
+	large, _ := r.Metrics[analyser.LargeParamsMetric].([]analyser.LargeParam)
+
+	if showSparkline {
+		fmt.Fprintf(w, "Exported functions per file: %s\n", sparkline(exported.PerFile, 10))
+	} else {
+		scale, err := histScale()
+		if err != nil {
+			return err
+		}
+		hist := histogram.Hist(5, exported.PerFile)
+		err = histogram.Fprint(w, hist, scale)
+		if err != nil {
+			return err
+		}
+	}
+	if noSummary {
+		return nil
+	}
+	if len(exported.PerFile) > 0 {
+		d := describe(exported.PerFile)
+		fmt.Fprintf(w, "Exported functions per file: mean %.2f, median %.4g, p90 %.4g, max %.4g\n", d.mean, d.median, d.p90, d.max)
+	}
+
+	if r.SampledFrom > 0 {
+		estimate := math.Round(float64(exported.Total) * float64(r.SampledFrom) / float64(r.Files))
+		fmt.Fprintf(w, "Package '%s' has an estimated %.0f exported function(s) across %d file(s)\n", r.Name, estimate, r.SampledFrom)
+		fmt.Fprintf(w, "Results are estimates from a random sample of %d file(s)\n", r.Files)
+	} else {
+		fmt.Fprintf(w, "Package '%s' has %d exported function(s) across %d file(s)\n", r.Name, exported.Total, r.Files)
+	}
+	if exported.OneLiners > 0 {
+		fmt.Fprintf(w, "%d of them are one-liners, %.0f%%\n", exported.OneLiners, 100*float64(exported.OneLiners)/float64(exported.Total))
+	}
+	if multi.Total > 0 {
+		fmt.Fprintf(w, "%d of them return multiple values, %d ending in an error\n", multi.Total, multi.EndingInError)
+	}
+	if len(variadic) > 0 {
+		names := []string{}
+		for _, v := range variadic {
+			names = append(names, v.Func)
+		}
+		fmt.Fprintf(w, "%d of them are variadic: %s\n", len(variadic), strings.Join(names, ", "))
+	}
+	if slices.Slices > 0 {
+		fmt.Fprintf(w, "%d of them take slices and %d only single values, %.0f%% favouring batches\n", slices.Slices, slices.Single, 100*slices.SliceRatio())
+	}
+	if len(verbs) > 0 {
+		vs := []string{}
+		for _, v := range verbs {
+			if len(vs) == maxVerbs {
+				break
+			}
+			vs = append(vs, fmt.Sprintf("%s (%d)", v.Verb, v.Count))
+		}
+		fmt.Fprintf(w, "Their names most often start with: %s\n", strings.Join(vs, ", "))
+	}
+	if len(options) > 0 {
+		names := []string{}
+		for _, o := range options {
+			names = append(names, fmt.Sprintf("%s (...%s)", o.Func, o.Option))
+		}
+		fmt.Fprintf(w, "%d of them take functional options: %s\n", len(options), strings.Join(names, ", "))
+		if built := analyser.OptionTypes(options); len(built) > 0 {
+			fmt.Fprintf(w, "  configuring %s\n", strings.Join(built, ", "))
+		}
+	}
+	if long := analyser.LongSignatures(signatures, maxSignatureLength); maxSignatureLength > 0 && len(long) > 0 {
+		parts := []string{}
+		for _, s := range long {
+			parts = append(parts, fmt.Sprintf("%s (%d)", s.Func, s.Length))
+		}
+		fmt.Fprintf(w, "%d of them have signatures over %d characters: %s\n", len(long), maxSignatureLength, strings.Join(parts, ", "))
+	}
+	if r.Command {
+		fmt.Fprintln(w, "This is an executable command")
+	} else {
+		fmt.Fprintln(w, "This is a library")
+	}
+	if r.Partial {
+		fmt.Fprintf(w, "Results are partial: analysis stopped after %d file(s)\n", maxFiles)
+	}
+	fmt.Fprintf(w, "Importing the following: %q\n", imports)
+	if domains := analyser.ImportDomains(imports); len(domains) > 0 {
+		external := 0
+		for _, n := range domains {
+			external += n
+		}
+		fmt.Fprintf(w, "  %d from the standard library, %d external by domain: %s\n", len(imports)-external, external, formatCounts(domains, "import(s)"))
+	}
+	if err := printImportsPerFile(w, fileImports); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Files average %.1f line(s) (standard deviation %.1f)\n", lines.Mean, lines.StdDev)
+	for _, o := range lines.Outliers(outlierFactor) {
+		fmt.Fprintf(w, "  %s has %d line(s), over %gx the mean\n", displayPath(o.File), o.Lines, outlierFactor)
+	}
+	if test, code := lines.TestLines(); code > 0 {
+		fmt.Fprintf(w, "Test-to-code ratio: %.2f, %d line(s) of tests to %d of code\n", float64(test)/float64(code), test, code)
+	}
+
+	if len(complexity) > 0 {
+		total, highest := 0, complexity[0]
+		for _, c := range complexity {
+			total += c.Complexity
+			if c.Complexity > highest.Complexity {
+				highest = c
+			}
+		}
+		fmt.Fprintf(w, "Cyclomatic complexity averages %.1f, highest is %d in %s\n", float64(total)/float64(len(complexity)), highest.Complexity, highest.Func)
+		top := analyser.ByFileComplexity(complexity)[0]
+		fmt.Fprintf(w, "  %s is the most complex file, %d across %d function(s)\n", displayPath(top.File), top.Complexity, top.Funcs)
+	}
+	if len(fanOut.Funcs) > 0 {
+		fmt.Fprintf(w, "Functions call %.1f distinct function(s) on average, most is %d in %s\n", fanOut.Average, fanOut.Highest.FanOut, fanOut.Highest.Func)
+	}
+	if len(flow.Jumps) > 0 {
+		fmt.Fprintf(w, "Control flow: %d label(s), %d goto(s), %d labelled break(s), %d labelled continue(s)\n", flow.Labels, flow.Gotos, flow.Breaks, flow.Continues)
+		for _, j := range flow.Jumps {
+			j.Pos.Filename = displayPath(j.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s %s\n", j.Pos, j.Kind, j.Label)
+		}
+	}
+	if long := analyser.LongFuncs(funcLines, maxFuncLines); maxFuncLines > 0 && len(long) > 0 {
+		fmt.Fprintf(w, "%d function(s) span more than %d lines:\n", len(long), maxFuncLines)
+		for _, f := range long {
+			f.Pos.Filename = displayPath(f.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s (%d lines)\n", f.Pos, f.Func, f.Lines)
+		}
+	}
+	if flow.Defers > 0 {
+		fmt.Fprintf(w, "Functions defer %.2f statement(s) on average, most is %d in %s\n", flow.DefersPerFunc(), flow.MostDefers.Defers, flow.MostDefers.Func)
+	}
+	if n := wrapping.Wrapped + wrapping.NotWrapped; n > 0 || wrapping.ErrorsNew > 0 {
+		fmt.Fprintf(w, "Errors: %d of %d fmt.Errorf call(s) wrap with %%w, %.0f%%, and %d come from errors.New\n",
+			wrapping.Wrapped, n, 100*wrapping.WrapRatio(), wrapping.ErrorsNew)
+	}
+	fmt.Fprintf(w, "Halstead volume is %.0f\n", halstead.Volume)
+	fmt.Fprintf(w, "Maintainability index is %.1f/100 (%s)\n", mi.Index, mi.Label)
+
+	if len(platforms) > 0 {
+		total := 0
+		for _, n := range platforms {
+			total += n
+		}
+		fmt.Fprintf(w, "Platform-specific files (%d): %s\n", total, formatCounts(platforms, "file(s)"))
+	}
+
+	if a := r.Activity; a != nil {
+		fmt.Fprintf(w, "Most recently changed file is %s on %s, least recently %s on %s\n",
+			displayPath(a.Newest.File), a.Newest.Date.Format("2006-01-02"), displayPath(a.Oldest.File), a.Oldest.Date.Format("2006-01-02"))
+	}
+
+	if len(tags) > 0 {
+		fmt.Fprintf(w, "Build tags guard files: %s\n", formatCounts(tags, "file(s)"))
+	}
+
+	if len(r.OtherFiles) > 0 {
+		fmt.Fprintf(w, "Non-Go source files: %s\n", formatCounts(r.OtherFiles, "file(s)"))
+	}
+
+	for _, p := range analyser.RiskyPackages {
+		if u, ok := risky[p]; ok {
+			fmt.Fprintf(w, "Uses %s in %d place(s) across %d file(s)\n", p, u.Sites, u.Files)
+		}
+	}
+
+	fmt.Fprintf(w, "%.0f%% of %d exported symbol(s) are documented, in %.1f word(s) on average\n", docs.Percent(), docs.Exported, docs.AverageWords)
+	if len(docs.Terse) > 0 {
+		names := []string{}
+		for _, s := range docs.Terse {
+			names = append(names, s.Name)
+		}
+		fmt.Fprintf(w, "  docs that only restate the name: %s\n", strings.Join(names, ", "))
+	}
+	if requireDocs && len(docs.Undocumented) > 0 {
+		fmt.Fprintln(w, "Undocumented exported symbol(s):")
+		for _, s := range docs.Undocumented {
+			s.Pos.Filename = displayPath(s.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s %s\n", s.Pos, s.Kind, s.Name)
+		}
+	}
+
+	if len(structTags) > 0 {
+		fmt.Fprintf(w, "Struct tags on exported structs: %s\n", formatCounts(structTags, "field(s)"))
+	}
+
+	if len(generic) > 0 {
+		counts, names := map[string]int{}, []string{}
+		for _, s := range generic {
+			counts[s.Kind]++
+			names = append(names, s.Name)
+		}
+		fmt.Fprintf(w, "Generics: %d exported func(s) and %d exported type(s) declare type parameters: %s\n", counts["func"], counts["type"], strings.Join(names, ", "))
+	}
+
+	if interfaces, concrete := analyser.InterfaceRatio(types); concrete > 0 {
+		fmt.Fprintf(w, "Exported types are %d interface(s) to %d concrete type(s), a ratio of %.2f\n", interfaces, concrete, float64(interfaces)/float64(concrete))
+	} else if interfaces > 0 {
+		fmt.Fprintf(w, "Exported types are all %d interface(s), likely an abstraction layer\n", interfaces)
+	}
+	if ranked := analyser.ByMethodCount(types); len(ranked) > 0 {
+		parts := []string{}
+		for _, t := range ranked {
+			parts = append(parts, fmt.Sprintf("%s (%d)", t.Name, len(t.Methods)))
+		}
+		fmt.Fprintf(w, "Exported methods per type: %s\n", strings.Join(parts, ", "))
+	}
+
+	if len(constructors) > 0 {
+		cs := []string{}
+		for _, c := range constructors {
+			cs = append(cs, fmt.Sprintf("%s (%s)", c.Func, c.Type))
+		}
+		fmt.Fprintf(w, "Constructors: %s\n", strings.Join(cs, ", "))
+	}
+
+	shared := []string{}
+	for _, m := range methodNames {
+		if len(m.Types) < 2 || len(shared) == maxSharedMethods {
+			break
+		}
+		shared = append(shared, fmt.Sprintf("%s on %d types", m.Name, len(m.Types)))
+	}
+	if len(shared) > 0 {
+		fmt.Fprintf(w, "Method names shared across types: %s\n", strings.Join(shared, ", "))
+	}
+
+	if len(unreferenced) > 0 {
+		fmt.Fprintf(w, "Exported function(s) not referenced within the package: %v\n", unreferenced)
+	}
+
+	if showEmptyInterface && len(emptyIfaces) > 0 {
+		fmt.Fprintf(w, "%d exported function(s) accept or return interface{}/any:\n", len(emptyIfaces))
+		for _, f := range emptyIfaces {
+			f.Pos.Filename = displayPath(f.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s\n", f.Pos, f.Func)
+		}
+	}
+
+	for _, i := range impls {
+		t := i.Type
+		if i.Pointer {
+			t = "*" + t
+		}
+		fmt.Fprintf(w, "Type %s implements interface %s\n", t, i.Interface)
+	}
+
+	if len(receivers) > 0 {
+		pointer, value, mixed := 0, 0, []analyser.ReceiverKinds{}
+		for _, k := range receivers {
+			pointer += k.Pointer
+			value += k.Value
+			if k.Mixed() {
+				mixed = append(mixed, k)
+			}
+		}
+		fmt.Fprintf(w, "Methods have %d pointer and %d value receiver(s)\n", pointer, value)
+		for _, k := range mixed {
+			k.Pos.Filename = displayPath(k.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s mixes them, %d pointer and %d value\n", k.Pos, k.Type, k.Pointer, k.Value)
+		}
+	}
+
+	if len(blanks) > 0 {
+		fmt.Fprintf(w, "%d assignment(s) to the blank identifier:\n", len(blanks))
+		for _, b := range blanks {
+			b.Pos.Filename = displayPath(b.Pos.Filename)
+			fmt.Fprintf(w, "  %s: in %s\n", b.Pos, b.Func)
+		}
+	}
+
+	if len(mutable) > 0 {
+		fmt.Fprintf(w, "%d exported signature(s) or field(s) expose maps or slices of pointers:\n", len(mutable))
+		for _, s := range mutable {
+			exposes := []string{}
+			if s.Maps {
+				exposes = append(exposes, "map")
+			}
+			if s.PointerSlices {
+				exposes = append(exposes, "[]*T")
+			}
+			s.Pos.Filename = displayPath(s.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s (%s)\n", s.Pos, s.Name, strings.Join(exposes, ", "))
+		}
+	}
+
+	if len(large) > 0 {
+		fmt.Fprintf(w, "%d parameter(s) pass structs of %d or more fields by value, a pointer may perform better:\n", len(large), analyser.LargeStructFields)
+		for _, p := range large {
+			p.Pos.Filename = displayPath(p.Pos.Filename)
+			fmt.Fprintf(w, "  %s: %s\n", p.Pos, p)
+		}
+	}
+
+	if suspected := analyser.SuspectedSecrets(secrets, secretEntropy); len(suspected) > 0 {
+		fmt.Fprintf(w, "%d string literal(s) look like hardcoded secrets:\n", len(suspected))
+		for _, s := range suspected {
+			s.Pos.Filename = displayPath(s.Pos.Filename)
+			if s.Kind == "high-entropy" {
+				fmt.Fprintf(w, "  %s: %d characters with an entropy of %.2f bits each\n", s.Pos, s.Length, s.Entropy)
+			} else {
+				fmt.Fprintf(w, "  %s: %s\n", s.Pos, s.Kind)
+			}
+		}
+	}
+
+	if showTypes {
+		printTypes(w, types)
+	}
+	if coverage != nil {
+		printCoverage(w, r)
+	}
+
+	if warnings := r.Warnings(); len(warnings) > 0 {
+		fmt.Fprintln(w, "Warnings:")
+		for _, warning := range warnings {
+			warning.Pos.Filename = displayPath(warning.Pos.Filename)
+			fmt.Fprintf(w, "  %s\n", warning)
+		}
+	}
+
+	if compareStd {
+		if err := printBaseline(w, r); err != nil {
+			return err
+		}
+	}
+	if baselineSymbols != nil {
+		printSymbolChanges(w, r, baselineSymbols)
+	}
+
+	for _, name := range analyser.Names() {
+		if _, isWarnings := r.Metrics[name].([]analyser.Warning); !printed[name] && !isWarnings {
+			fmt.Fprintf(w, "%s: %v\n", name, r.Metrics[name])
+		}
+	}
+	if explain {
+		printExplanations(w)
+	}
+
+	return nil
+}
+
+// printExplanations describes what each metric measures and how to read it.
+func printExplanations(w io.Writer) {
+	fmt.Fprintln(w, "Metrics explained:")
+	for _, name := range analyser.Names() {
+		if d := analyser.Describe(name); d != "" {
+			fmt.Fprintf(w, "  %s: %s\n", name, d)
+		}
+	}
+}
+
+// displayRoot is the directory or GitHub path being analysed, which file
+// paths are printed relative to when --relative is set.
+var displayRoot string
+
+// displayPath returns the path of a file as it should be printed.
+func displayPath(name string) string {
+	if !relativePaths || displayRoot == "" {
+		return name
+	}
+	rel, err := filepath.Rel(displayRoot, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return name
+	}
+	return rel
+}
+
+// printImportsPerFile draws the distribution of imports across files and
+// names the file importing the most.
+func printImportsPerFile(w io.Writer, files []analyser.FileImports) error {
+	if len(files) == 0 {
+		return nil
+	}
+	counts := []float64{}
+	most := files[0]
+	for _, f := range files {
+		counts = append(counts, float64(f.Imports))
+		if f.Imports > most.Imports {
+			most = f
+		}
+	}
+
+	if showSparkline {
+		fmt.Fprintf(w, "Imports per file: %s\n", sparkline(counts, 10))
+	} else {
+		scale, err := histScale()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "Imports per file:")
+		if err := histogram.Fprint(w, histogram.Hist(5, counts), scale); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "  %s imports the most, %d package(s)\n", displayPath(most.File), most.Imports)
+	return nil
+}
+
+// maxSharedMethods is how many of the method names declared on the most
+// types are printed.
+const maxSharedMethods = 5
+
+// maxVerbs is how many of the most frequent first words of exported function
+// names are printed.
+const maxVerbs = 10
+
+func printBaseline(w io.Writer, r analyser.Result) error {
+	b, err := baseline.Load()
+	if err != nil {
+		return fmt.Errorf("loading baseline: %w", err)
+	}
+
+	fmt.Fprintf(w, "Compared with %d standard library packages (%s):\n", b.Packages, b.GoVersion)
+	values := baseline.Values(r)
+	for _, name := range baseline.Names {
+		p, ok := b.Percentile(name, values[name])
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "  %s is %.4g, in the %s percentile\n", name, values[name], ordinal(p))
+	}
+	return nil
+}
+
+// ordinal formats n as 1st, 2nd, 3rd and so on.
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func printTypes(w io.Writer, types []analyser.Type) {
+	fmt.Fprintln(w, "Types:")
+	for i, t := range types {
+		branch, indent := "├── ", "│   "
+		if i == len(types)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s\n", branch, t.Name)
+		for j, m := range t.Methods {
+			leaf := "├── "
+			if j == len(t.Methods)-1 {
+				leaf = "└── "
+			}
+			fmt.Fprintf(w, "%s%s%s\n", indent, leaf, m)
+		}
+	}
+}
+
+// formatCounts lists counts from the most to the least, each followed by
+// unit.
+func formatCounts(counts map[string]int, unit string) string {
+	names := []string{}
+	for p := range counts {
+		names = append(names, p)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := []string{}
+	for _, p := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d %s", p, counts[p], unit))
+	}
+	return strings.Join(parts, ", ")
+}
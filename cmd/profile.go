@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts a CPU profile if --cpuprofile is set. The returned
+// func stops it and writes a heap profile if --memprofile is set.
+func startProfiling() (func() error, error) {
+	var cpu *os.File
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("creating cpu profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("starting cpu profile: %w", err)
+		}
+		cpu = f
+	}
+
+	return func() error {
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				return fmt.Errorf("writing cpu profile: %w", err)
+			}
+		}
+		if memProfile == "" {
+			return nil
+		}
+
+		f, err := os.Create(memProfile)
+		if err != nil {
+			return fmt.Errorf("creating memory profile: %w", err)
+		}
+		defer f.Close()
+		runtime.GC() // up to date statistics
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return fmt.Errorf("writing memory profile: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	proxyFlag  string
+	caCertFile string
+)
+
+// transport carries every request made to GitHub. It's configured from
+// --proxy and --ca-cert before any command runs.
+var transport http.RoundTripper = http.DefaultTransport
+
+// configureTransport builds transport from the default one, sending requests
+// through --proxy if set and otherwise the proxy named by the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables, and trusting the
+// certificates in --ca-cert on top of the system's, as needed behind a proxy
+// that intercepts TLS.
+func configureTransport() error {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if proxyFlag != "" {
+		u, err := url.Parse(proxyFlag)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid --proxy %q, expected a URL such as http://proxy:8080", proxyFlag)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if caCertFile != "" {
+		pem, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return fmt.Errorf("reading --ca-cert: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates found in %s", caCertFile)
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	transport = t
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&proxyFlag, "proxy", "", "Proxy URL for requests to GitHub, defaults to $HTTPS_PROXY or $HTTP_PROXY")
+	rootCmd.PersistentFlags().StringVar(&caCertFile, "ca-cert", "", "PEM file of extra CA certificates to trust, such as those of a TLS intercepting proxy")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := applyFormatEnv(cmd); err != nil {
+			// the command line isn't at fault
+			cmd.SilenceUsage = true
+			return err
+		}
+		return configureTransport()
+	}
+}
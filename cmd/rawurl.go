@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// isRawURL reports whether pkg is an http or https URL of a provider not
+// otherwise supported, fetched as a single Go file.
+func isRawURL(pkg string) bool {
+	return strings.HasPrefix(pkg, "https://") || strings.HasPrefix(pkg, "http://")
+}
+
+// parseRawURL analyses the single Go file at a URL, such as a link to the raw
+// contents of a file or a paste. Positions name the file by the last element
+// of the URL's path.
+func parseRawURL(pkg string) (target, error) {
+	u, err := url.Parse(pkg)
+	if err != nil {
+		return target{}, fmt.Errorf("parsing url: %w", err)
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = u.Host
+	}
+
+	stop := startPhase(phaseFetch)
+	src, err := fetchRaw(pkg)
+	stop()
+	if err != nil {
+		return target{}, fmt.Errorf("getting %s: %w", pkg, err)
+	}
+
+	fset := token.NewFileSet() // positions are relative to fset
+	stop = startPhase(phaseParse)
+	f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+	stop()
+	if err != nil {
+		return target{}, fmt.Errorf("%s isn't a Go file: %w", pkg, err)
+	}
+
+	return target{results: analysePackages(pkg, fset, []*ast.File{f}, false)}, nil
+}
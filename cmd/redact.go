@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// redactor replaces file and package paths with anonymous identifiers, the
+// same path always getting the same one within a run.
+type redactor struct {
+	ids    map[string]string
+	counts map[string]int
+	// modules are the paths of the modules analysed, the import paths
+	// beneath which are replaced wherever they appear.
+	modules []string
+}
+
+func newRedactor() *redactor {
+	return &redactor{ids: map[string]string{}, counts: map[string]int{}}
+}
+
+// file returns the identifier of a file, such as file3.go. The _test.go
+// suffix is kept, as metrics tell tests from code by it.
+func (r *redactor) file(name string) string {
+	if name == "" {
+		return ""
+	}
+	if id, ok := r.ids["file "+name]; ok {
+		return id
+	}
+	r.counts["file"]++
+	suffix := filepath.Ext(name)
+	if strings.HasSuffix(name, "_test.go") {
+		suffix = "_test.go"
+	}
+	id := fmt.Sprintf("file%d%s", r.counts["file"], suffix)
+	r.ids["file "+name] = id
+	return id
+}
+
+// pkg returns the identifier of a package, such as pkg2, by its directory or
+// import path.
+func (r *redactor) pkg(name string) string {
+	if name == "" {
+		return ""
+	}
+	if id, ok := r.ids["pkg "+name]; ok {
+		return id
+	}
+	r.counts["pkg"]++
+	id := fmt.Sprintf("pkg%d", r.counts["pkg"])
+	r.ids["pkg "+name] = id
+	return id
+}
+
+// importPath returns the identifier of the package with an import path,
+// keeping the domain it's hosted on, such as github.com/pkg2, so imports are
+// still told from those of the standard library and counted by domain.
+func (r *redactor) importPath(p string) string {
+	if host := strings.SplitN(p, "/", 2)[0]; strings.Contains(host, ".") {
+		return host + "/" + r.pkg(p)
+	}
+	return r.pkg(p)
+}
+
+// redactTargets anonymises the paths of every package analysed, along with
+// the import paths of the modules analysed wherever they appear, leaving the
+// names of packages and symbols along with every count as they are. Files
+// are numbered in the order the packages list them. The coverage loaded
+// with --coverage is rekeyed to match.
+func redactTargets(targets []target) {
+	r := newRedactor()
+	r.modules = analysedModules(targets)
+	covered := map[string]fileCoverage{}
+	for i := range targets {
+		t := &targets[i]
+		t.root = ""
+		if t.module != nil {
+			// the summary lists directories relative to the module, which
+			// are given the identifiers of the import paths they hold
+			mod := moduleImportPath(t.results, t.module.packages)
+			for j, p := range t.module.packages {
+				if mod != "" {
+					p = path.Join(mod, p)
+				}
+				t.module.packages[j] = r.pkg(p)
+			}
+			for _, c := range t.module.cycles {
+				for j, p := range c {
+					c[j] = r.importPath(p)
+				}
+			}
+		}
+
+		for j := range t.results {
+			res := &t.results[j]
+			lines, _ := res.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+			key := res.ImportPath
+			if key == "" {
+				key = res.Path
+			}
+			id := r.pkg(key)
+			importPath := ""
+			if res.ImportPath != "" {
+				importPath = r.importPath(res.ImportPath)
+			}
+			for _, f := range lines.Files {
+				c, ok := coverage[path.Join(res.ImportPath, filepath.Base(f.File))]
+				name := r.file(f.File)
+				if ok && res.ImportPath != "" {
+					covered[path.Join(importPath, name)] = c
+				}
+			}
+
+			res.Path = id
+			res.ImportPath = importPath
+			r.redact(reflect.ValueOf(res).Elem())
+		}
+	}
+	if coverage != nil {
+		coverage = covered
+	}
+}
+
+// moduleImportPath returns the import path of the module a summary lists
+// packages of, taken from the result of a package whose import path ends with
+// its directory relative to the module's root, or "" if none does. The
+// longest directory matching is the package's own.
+func moduleImportPath(results []analyser.Result, packages []string) string {
+	for _, res := range results {
+		longest := ""
+		for _, p := range packages {
+			if p != "." && len(p) > len(longest) && strings.HasSuffix(res.ImportPath, "/"+p) {
+				longest = p
+			}
+		}
+		if longest != "" {
+			return strings.TrimSuffix(res.ImportPath, "/"+longest)
+		}
+	}
+	if len(results) == 1 {
+		return results[0].ImportPath
+	}
+	return ""
+}
+
+// analysedModules returns the paths of the modules holding the packages
+// analysed, the longest first so the innermost of nested modules matches.
+func analysedModules(targets []target) []string {
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if t.module != nil {
+			if mod := moduleImportPath(t.results, t.module.packages); mod != "" {
+				seen[mod] = true
+			}
+		}
+		for _, res := range t.results {
+			if res.ImportPath == "" {
+				continue
+			}
+			if mod, ok := findModule(res.Path); ok && mod.importPath(res.Path) == res.ImportPath {
+				seen[mod.path] = true
+			}
+		}
+	}
+	out := []string{}
+	for m := range seen {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+	return out
+}
+
+var positionType = reflect.TypeOf(token.Position{})
+
+// pathToken matches what could be an import path within a string.
+var pathToken = regexp.MustCompile(`[A-Za-z0-9_.~/-]+`)
+
+// text replaces the import paths within the modules analysed found in s,
+// whether s is one or a message naming them, with their identifiers.
+func (r *redactor) text(s string) string {
+	if len(r.modules) == 0 {
+		return s
+	}
+	return pathToken.ReplaceAllStringFunc(s, func(token string) string {
+		// a path ending a sentence keeps its full stop
+		trimmed := strings.TrimRight(token, ".")
+		for _, m := range r.modules {
+			if trimmed == m || strings.HasPrefix(trimmed, m+"/") {
+				return r.importPath(trimmed) + token[len(trimmed):]
+			}
+		}
+		return token
+	})
+}
+
+// redact replaces the file names found in v, the Filename of positions and
+// the File of anything naming a file, and the import paths within the
+// modules analysed found in any other string. v must be settable.
+func (r *redactor) redact(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			r.redact(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// the value held isn't settable, so a copy is redacted instead
+		c := reflect.New(v.Elem().Type()).Elem()
+		c.Set(v.Elem())
+		r.redact(c)
+		v.Set(c)
+	case reflect.Struct:
+		if v.Type() == positionType {
+			f := v.FieldByName("Filename")
+			f.SetString(r.file(f.String()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+			if v.Type().Field(i).Name == "File" && f.Kind() == reflect.String {
+				f.SetString(r.file(f.String()))
+				continue
+			}
+			r.redact(f)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.redact(v.Index(i))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(r.text(v.String()))
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			c := reflect.New(v.Type().Elem()).Elem()
+			c.Set(v.MapIndex(k))
+			r.redact(c)
+			if k.Kind() == reflect.String {
+				if key := r.text(k.String()); key != k.String() {
+					v.SetMapIndex(k, reflect.Value{})
+					k = reflect.ValueOf(key).Convert(k.Type())
+				}
+			}
+			v.SetMapIndex(k, c)
+		}
+	}
+}
@@ -1,173 +1,255 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io/fs"
 	"log"
-	"net/url"
 	"os"
-	"sort"
+	"runtime"
 	"strings"
+	"time"
 
-	"github.com/aybabtme/uniplot/histogram"
-	"github.com/google/go-github/v33/github"
 	"github.com/spf13/cobra"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+var (
+	showTypes     bool
+	outputDir     string
+	histWidth     int
+	histScaleName string
+	recursive     bool
+	maxFiles      int
+	outlierFactor float64
+	compareStd    bool
+	relativePaths bool
+	tokenFlag     string
+	showSparkline bool
+	fromFile      string
+	concurrency   int
+	includeVendor bool
+	showTiming    bool
+	interactive   bool
+	cpuProfile    string
+	memProfile    string
+	refFlag       string
+	requireDocs   bool
+	strict        bool
+	sampleSize    int
+	sampleSeed    int64
+	showActivity  bool
+	metricsNDJSON bool
+	packageFilter []string
+	ignoreCase    bool
+	providerFlag  string
+	mergePackages bool
+	sarifFile     string
+	baselineFile  string
+	typedMode     bool
+	noSummary     bool
+	mermaid       bool
+	sqliteFile    string
+	secretEntropy float64
+	refsFlag      []string
+	countOnly     bool
+	countMetric   string
+	explain       bool
+	dotCallGraph  bool
+	jsonOut       bool
+	jsonPretty    bool
+	coverageFile  string
+	useCache      bool
+	redact        bool
+
+	maxSignatureLength int
+	maxFuncLines       int
+
+	excludePackages []string
+
+	showEmptyInterface bool
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "package-analyser",
 	Short: "Analyses packages to give a 100ft view of how they look",
+	Long: `Analyses packages to give a 100ft view of how they look.
+
+The output format of every command defaults to $PACKAGE_ANALYSER_FORMAT when
+set, one of text, json, json-pretty, metrics-ndjson, mermaid or call-graph.
+Any of those flags given on the command line takes precedence.`,
+	Args: packageArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := run(args[0]); err != nil {
+		if _, err := histScale(); err != nil {
 			log.Fatal(err)
 		}
-	},
-}
-
-func dirFilter(f fs.FileInfo) bool { return true }
-
-func run(pkg string) error {
-	if strings.HasPrefix(pkg, "github.com") {
-		return parseGithubPackage(pkg)
-	}
-
-	return parseLocalPackage(pkg)
-}
-
-func parseGithubPackage(pkg string) error {
-	u, err := url.Parse(pkg)
-	if err != nil {
-		return fmt.Errorf("parsing url: %w", err)
-	}
-
-	s := strings.Split(u.Path, "/")
-	if len(s) < 3 {
-		return fmt.Errorf("package not specified")
-	}
-	path := strings.Join(s[3:], "/")
-
-	client := github.NewClient(nil)
-
-	_, dirC, _, err := client.Repositories.GetContents(context.Background(), s[1], s[2], path, nil)
-	if err != nil {
-		return fmt.Errorf("getting package: %w", err)
-	}
-
-	publicFunctions := 0
-	fileCount := 0
-	data := []float64{}
-	imports := make(map[string]bool)
-
-	for _, f := range dirC {
-		if !strings.HasSuffix(f.GetName(), ".go") {
-			continue
+		if err := checkCountMetric(); err != nil {
+			log.Fatal(err)
 		}
-		fileCount++
-		fileC, _, _, err := client.Repositories.GetContents(context.Background(), s[1], s[2], f.GetPath(), nil)
-		if err != nil {
-			return fmt.Errorf("getting file: %w", err)
+		if secretEntropy < analyser.MinSecretEntropy {
+			log.Fatalf("--secret-entropy must be at least %g, lower entropies aren't recorded", analyser.MinSecretEntropy)
 		}
-		c, err := fileC.GetContent()
-		if err != nil {
-			return fmt.Errorf("getting file contents: %w", err)
+		if coverageFile != "" {
+			var err error
+			if coverage, err = loadCoverage(coverageFile); err != nil {
+				log.Fatal(err)
+			}
 		}
-
-		fset := token.NewFileSet() // positions are relative to fset
-
-		fp, err := parser.ParseFile(fset, f.GetName(), c, parser.ParseComments)
+		if baselineFile != "" {
+			var err error
+			if baselineSymbols, err = loadSavedSymbols(baselineFile); err != nil {
+				log.Fatal(err)
+			}
+		}
+		stopProfiling, err := startProfiling()
 		if err != nil {
-			return fmt.Errorf("parsing file: %w", err)
+			log.Fatal(err)
 		}
-
-		publicFuncsPerFile := 0.
-		for _, d := range fp.Decls {
-			if fn, isFn := d.(*ast.FuncDecl); isFn && ast.IsExported(fn.Name.Name) {
-				publicFunctions++
-				publicFuncsPerFile++
+		defer func() {
+			if err := stopProfiling(); err != nil {
+				log.Fatal(err)
+			}
+		}()
+		// exit fails the run, stopping profiling first as os.Exit skips the
+		// deferred call: a failing run is the one worth profiling
+		exit := func(code int) {
+			if err := stopProfiling(); err != nil {
+				log.Print(err)
 			}
+			os.Exit(code)
 		}
-		data = append(data, publicFuncsPerFile)
 
-		for _, i := range fp.Imports {
-			imports[i.Path.Value] = true
+		pkgs := args
+		if fromFile != "" {
+			listed, err := readPackageList(fromFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			pkgs = append(pkgs, listed...)
 		}
-	}
-	hist := histogram.Hist(5, data)
-	err = histogram.Fprint(os.Stdout, hist, histogram.Linear(20))
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("Package has %d exported function(s) across %d file(s)\n", publicFunctions, fileCount)
 
-	i := alphabetical(toSlice(imports))
-	sort.Sort(i)
-	fmt.Printf("Importing the following: %v\n", i)
-
-	return nil
-}
-
-func parseLocalPackage(pkg string) error {
-	fset := token.NewFileSet() // positions are relative to fset
-	pkgs, err := parser.ParseDir(fset, pkg, dirFilter, parser.ParseComments)
-	if err != nil {
-		return err
-	}
-	for _, pkg := range pkgs {
-		publicFunctions := 0
-		fileCount := 0
-		data := []float64{}
-		imports := make(map[string]bool)
-
-		for _, f := range pkg.Files {
-			fileCount++
-			publicFuncsPerFile := 0.
-			for _, d := range f.Decls {
-				if fn, isFn := d.(*ast.FuncDecl); isFn && ast.IsExported(fn.Name.Name) {
-					publicFunctions++
-					publicFuncsPerFile++
-				}
+		if len(refsFlag) > 0 {
+			if refFlag != "" {
+				log.Fatal("--ref can't be combined with --refs")
 			}
-			data = append(data, publicFuncsPerFile)
-
-			for _, i := range f.Imports {
-				imports[i.Path.Value] = true
+			if err := printEvolution(os.Stdout, pkgs, refsFlag); err != nil {
+				log.Fatal(err)
 			}
+			return
 		}
-		hist := histogram.Hist(5, data)
-		err := histogram.Fprint(os.Stdout, hist, histogram.Linear(20))
+
+		start := time.Now()
+		targets, err := analyseAll(pkgs, refFlag, concurrency)
 		if err != nil {
-			return err
+			log.Fatal(err)
 		}
+		targets = selectPackages(targets)
+		if redact {
+			redactTargets(targets)
+		}
+		if countOnly {
+			if err := printCount(os.Stdout, targets); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		if interactive {
+			if err := runTUI(targets); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		for _, t := range targets {
+			if err := outputTarget(t); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if !metricsNDJSON {
+			printRunSummary(os.Stdout, targets)
+		}
+		if sarifFile != "" {
+			if err := writeSARIF(sarifFile, targets); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if sqliteFile != "" {
+			if err := writeSQLite(sqliteFile, start, targets); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if requireDocs {
+			if n := undocumented(targets); n > 0 {
+				log.Printf("%d exported symbol(s) lack a doc comment", n)
+				exit(1)
+			}
+		}
+		if strict {
+			if v := violations(targets); len(v) > 0 {
+				printViolations(os.Stderr, v)
+				exit(1)
+			}
+		}
+		if showTiming {
+			printTimings(os.Stderr, time.Since(start))
+		}
+	},
+}
 
-		fmt.Printf("Package '%s' has %d exported function(s) across %d file(s)\n", pkg.Name, publicFunctions, fileCount)
-
-		i := alphabetical(toSlice(imports))
-		sort.Sort(i)
-		fmt.Printf("Importing the following: %v\n", i)
+// packageArgs requires at least one package to analyse, given either as an
+// argument or through --from-file, so that running without any prints usage
+// rather than doing nothing.
+func packageArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && fromFile == "" {
+		return fmt.Errorf("requires at least one package, or --from-file")
 	}
-
 	return nil
 }
 
-func toSlice(is map[string]bool) []string {
-	out := []string{}
-	for i := range is {
-		out = append(out, i)
+// run analyses pkg as of the git branch, tag or commit ref, or if empty as it
+// is now.
+func run(pkg, ref string) (target, error) {
+	switch providerFlag {
+	case "":
+	case "github":
+		if !strings.HasPrefix(pkg, "github.com") && !strings.HasPrefix(pkg, "https://github.com") {
+			pkg = "github.com/" + strings.TrimPrefix(pkg, "/")
+		}
+	default:
+		return target{}, fmt.Errorf("unknown provider %q, only github is supported", providerFlag)
 	}
-	return out
+
+	if isRemote(pkg) && typedMode {
+		return target{}, fmt.Errorf("--typed only works for local packages, not %s", pkg)
+	}
+
+	if strings.HasPrefix(pkg, "gist.github.com") || strings.HasPrefix(pkg, "https://gist.github.com") {
+		return parseGist(pkg)
+	}
+	if strings.HasPrefix(pkg, "github.com") || strings.HasPrefix(pkg, "https://github.com") {
+		return parseGithubPackage(pkg, ref)
+	}
+	if isRawURL(pkg) {
+		return parseRawURL(pkg)
+	}
+
+	return parseLocalPackage(pkg, ref)
 }
 
-type alphabetical []string
+// isRemote reports whether pkg names a package on GitHub, a gist or a file
+// at a URL rather than a local directory.
+func isRemote(pkg string) bool {
+	return strings.HasPrefix(pkg, "github.com") || strings.HasPrefix(pkg, "gist.github.com") || isRawURL(pkg)
+}
 
-func (a alphabetical) Len() int           { return len(a) }
-func (a alphabetical) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a alphabetical) Less(i, j int) bool { return a[i] < a[j] }
+// analyse runs every registered metric over the files of the package found
+// at path.
+func analyse(path string, fset *token.FileSet, files []*ast.File) analyser.Result {
+	defer startPhase(phaseAnalyse)()
+	r := analyser.Analyse(fset, files)
+	r.Path = path
+	return r
+}
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
@@ -176,7 +258,62 @@ func Execute() {
 }
 
 func init() {
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.Flags().BoolVar(&showTypes, "types", false, "Print exported types along with their exported methods")
+	rootCmd.Flags().BoolVar(&showEmptyInterface, "empty-interface", false, "Print exported functions that accept or return interface{} or any")
+	rootCmd.Flags().StringVar(&outputDir, "output-dir", "", "Write each package's result as JSON to a file in this directory instead of printing it")
+	rootCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Analyse every package beneath a local directory or GitHub path")
+	rootCmd.Flags().BoolVar(&typedMode, "typed", false, "Type check local packages with go/packages for more precise results, much slower as dependencies are type checked from source")
+	rootCmd.Flags().StringSliceVar(&excludePackages, "exclude-package", nil, "Skip packages whose import path matches these globs when recursing, pattern/... also skipping those beneath")
+	rootCmd.Flags().BoolVar(&useCache, "cache", false, "Reuse the results of metrics computed by a previous run with --cache from local files that haven't changed since")
+	rootCmd.Flags().BoolVar(&includeVendor, "include-vendor", false, "Analyse vendor directories when recursing")
+	rootCmd.Flags().BoolVar(&mergePackages, "merge-packages", false, "Analyse the packages sharing a directory, such as external tests, as one")
+	rootCmd.Flags().IntVar(&maxFiles, "max-files", 0, "Stop fetching a GitHub package after this many files, 0 for no limit")
+	rootCmd.Flags().Float64Var(&outlierFactor, "outlier-factor", 2, "Flag files longer than this many times the mean file length")
+	rootCmd.Flags().BoolVar(&compareStd, "baseline", false, "Compare the package with the packages of the standard library")
+	rootCmd.Flags().StringVar(&baselineFile, "baseline-file", "", "List the exported symbols added and removed since a package's result saved with --output-dir")
+	rootCmd.Flags().BoolVar(&redact, "redact", false, "Replace file and package paths with identifiers such as file1.go and pkg1, to share results without revealing the layout of the code")
+	rootCmd.Flags().BoolVar(&relativePaths, "relative", true, "Print file paths relative to the package or directory being analysed")
+	rootCmd.Flags().StringVar(&refFlag, "ref", "", "Analyse the package as of this git branch, tag or commit rather than the working tree")
+	rootCmd.Flags().StringSliceVar(&refsFlag, "refs", nil, "Analyse the package at each of these git branches, tags or commits and print a table of how it changed")
+	rootCmd.Flags().StringVar(&providerFlag, "provider", "", "Where packages are hosted, github to allow owner/repo/path without github.com")
+	rootCmd.Flags().StringVar(&tokenFlag, "token", "", "GitHub token, defaults to $GITHUB_TOKEN, the token of the gh CLI or the github.com password in ~/.netrc")
+	rootCmd.Flags().BoolVar(&noSummary, "no-summary", false, "Print only the histogram of exported functions per file for each package")
+	rootCmd.Flags().BoolVar(&countOnly, "count-only", false, "Print only the number of exported functions, or of --count-metric, across every package")
+	rootCmd.Flags().StringVar(&countMetric, "count-metric", analyser.ExportedFuncsMetric, "Metric counted by --count-only, the length of its list or its total")
+	rootCmd.Flags().BoolVar(&explain, "explain", false, "Describe what each metric measures and how to interpret it after the results of each package")
+	rootCmd.Flags().BoolVar(&showSparkline, "sparkline", false, "Draw the histogram as a single line")
+	rootCmd.Flags().StringVar(&fromFile, "from-file", "", "Read packages to analyse from a file, one per line")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", runtime.NumCPU(), "Analyse at most this many packages at once, 0 for no limit")
+	rootCmd.Flags().BoolVar(&showTiming, "timing", false, "Print how long fetching, parsing and analysing took to stderr")
+	rootCmd.Flags().BoolVar(&interactive, "tui", false, "Browse the results in an interactive terminal UI")
+	rootCmd.Flags().StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile to this file")
+	rootCmd.Flags().StringVar(&memProfile, "memprofile", "", "Write a memory profile to this file when done")
+	rootCmd.Flags().BoolVar(&requireDocs, "require-docs", false, "List exported symbols without a doc comment and fail if there are any")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "Fail, listing every problem, if there are warnings, overly complex functions, overly long files or functions, undocumented exported symbols or poorly maintainable packages")
+	rootCmd.Flags().IntVar(&sampleSize, "sample", 0, "Estimate the results of each package from a random sample of this many files, 0 to analyse every file")
+	rootCmd.Flags().Int64Var(&sampleSeed, "seed", 1, "Seed for choosing the files sampled by --sample")
+	rootCmd.Flags().BoolVar(&showActivity, "activity", false, "Look up when each file of a GitHub package was last changed, one API call per file")
+	rootCmd.PersistentFlags().BoolVar(&mermaid, "mermaid", false, "Print a Mermaid class diagram of each package's types, the interfaces they implement and the types they embed instead")
+	rootCmd.PersistentFlags().BoolVar(&dotCallGraph, "call-graph", false, "Print a Graphviz DOT graph of the calls between each package's functions instead, resolved by name so only calls within the package")
+	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "Print each package's result as a line of JSON instead")
+	rootCmd.PersistentFlags().BoolVar(&jsonPretty, "json-pretty", false, "Print each package's result as indented JSON instead")
+	rootCmd.PersistentFlags().BoolVar(&metricsNDJSON, "metrics-ndjson", false, "Print each metric of each package as a timestamped line of JSON instead")
+	rootCmd.Flags().StringSliceVar(&packageFilter, "package", nil, "Only output packages with these names")
+	rootCmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Match names given to --package regardless of case")
+	rootCmd.Flags().StringVar(&coverageFile, "coverage", "", "Show the statement coverage of each file from this profile, written by go test -coverprofile, next to its exported functions")
+	rootCmd.Flags().StringVar(&sarifFile, "sarif", "", "Also write the findings of every package, such as warnings and overly complex functions, to this file as SARIF")
+	rootCmd.Flags().StringVar(&sqliteFile, "sqlite", "", "Also record the results of every package as a run in this SQLite database, creating it if needed")
+	rootCmd.Flags().Float64Var(&secretEntropy, "secret-entropy", 4, "Flag string literals of 20 or more characters with at least this many bits of entropy per character as possible secrets, from 3.5 up, 20 characters reaching at most 4.32")
+	rootCmd.Flags().IntVar(&maxSignatureLength, "max-signature-length", 120, "Flag exported functions whose signature is longer than this many characters, 0 to never flag")
+	rootCmd.Flags().IntVar(&maxFuncLines, "max-func-lines", 0, "List functions spanning more than this many lines, failing --strict if there are any, 0 to never list")
+	rootCmd.Flags().IntVar(&histWidth, "hist-width", 20, "Width of the longest histogram bar")
+	rootCmd.Flags().StringVar(&histScaleName, "hist-scale", "linear", "Scale of histogram bars, linear or log")
+
+	cobra.CheckErr(rootCmd.MarkFlagDirname("output-dir"))
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("hist-scale", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"linear", "log"}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("count-metric", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return analyser.Names(), cobra.ShellCompDirectiveNoFileComp
+	}))
 }
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// sampleIndices picks --sample of n files at random, returning their indices
+// in order. The choice only depends on --seed and n, so runs are reproducible
+// however many packages are analysed at once. Every index is returned when
+// not sampling or when there are no more than --sample files.
+func sampleIndices(n int) []int {
+	if sampleSize <= 0 || n <= sampleSize {
+		out := make([]int, n)
+		for i := range out {
+			out[i] = i
+		}
+		return out
+	}
+
+	rng := rand.New(rand.NewSource(sampleSeed))
+	out := rng.Perm(n)[:sampleSize]
+	sort.Ints(out)
+	return out
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// sarifRule is a reportingDescriptor. The description and severity of an
+// analyser.Rule are flattened into the nested objects SARIF expects when
+// marshalled.
+type sarifRule analyser.Rule
+
+func (r sarifRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"id":                   r.ID,
+		"shortDescription":     sarifMessage{Text: r.Description},
+		"defaultConfiguration": map[string]string{"level": r.Severity},
+	})
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn,omitempty"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// sarifFindings converts the findings of every package analysed to SARIF
+// results.
+func sarifFindings(targets []target) []sarifResult {
+	out := []sarifResult{}
+	for _, t := range targets {
+		for _, r := range t.results {
+			for _, f := range r.Findings {
+				var loc sarifLocation
+				loc.PhysicalLocation.ArtifactLocation.URI = sarifURI(f.File)
+				loc.PhysicalLocation.Region.StartLine = f.Line
+				loc.PhysicalLocation.Region.StartColumn = f.Column
+				out = append(out, sarifResult{RuleID: f.Rule, Level: f.Severity, Message: sarifMessage{Text: f.Message}, Locations: []sarifLocation{loc}})
+			}
+		}
+	}
+	return out
+}
+
+// sarifURI turns a file path into the URI of a SARIF artifact location. Paths
+// are kept relative to the working directory, rather than to the package as
+// --relative prints them, so that they resolve from the root of a repository
+// analysed in CI.
+func sarifURI(name string) string {
+	name = filepath.Clean(name)
+	if filepath.IsAbs(name) {
+		return "file://" + filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(name)
+}
+
+func sarifRules() []sarifRule {
+	out := []sarifRule{}
+	for _, r := range analyser.Rules {
+		out = append(out, sarifRule(r))
+	}
+	return out
+}
+
+// writeSARIF writes the findings of every package analysed to name as a
+// SARIF 2.1.0 log, as accepted by GitHub code scanning.
+func writeSARIF(name string, targets []target) error {
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "package-analyser",
+				InformationURI: "https://github.com/trelore/package-analyser",
+				Rules:          sarifRules(),
+			}},
+			Results: sarifFindings(targets),
+		}},
+	}
+
+	b, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding SARIF: %w", err)
+	}
+	if err := os.WriteFile(name, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing SARIF: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// registers the pure Go "sqlite" driver, so no cgo is needed
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema is created in a database the first time a run is written to
+// it. Each run records the packages analysed, and each package the JSON of
+// its metrics, so trends can be queried with SQLite's JSON functions.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id         INTEGER PRIMARY KEY,
+	started_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS packages (
+	id          INTEGER PRIMARY KEY,
+	run_id      INTEGER NOT NULL REFERENCES runs(id),
+	name        TEXT NOT NULL,
+	path        TEXT NOT NULL,
+	import_path TEXT NOT NULL,
+	files       INTEGER NOT NULL,
+	UNIQUE (run_id, path, name)
+);
+CREATE TABLE IF NOT EXISTS metrics (
+	package_id INTEGER NOT NULL REFERENCES packages(id),
+	name       TEXT NOT NULL,
+	value      TEXT NOT NULL,
+	PRIMARY KEY (package_id, name)
+);
+`
+
+// writeSQLite records the results of every package analysed as a new run in
+// the SQLite database name, creating it if needed. A package analysed twice
+// in a run, such as when listed twice, replaces its earlier rows.
+func writeSQLite(name string, started time.Time, targets []target) error {
+	db, err := sql.Open("sqlite", name)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", name, err)
+	}
+	defer db.Close()
+
+	if _, err = db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("creating schema in %s: %w", name, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	if err := insertRun(tx, started, targets); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("writing to %s: %w", name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("writing to %s: %w", name, err)
+	}
+	return nil
+}
+
+func insertRun(tx *sql.Tx, started time.Time, targets []target) error {
+	res, err := tx.Exec(`INSERT INTO runs (started_at) VALUES (?)`, started.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("inserting run: %w", err)
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("inserting run: %w", err)
+	}
+
+	for _, t := range targets {
+		for _, r := range t.results {
+			var pkgID int64
+			err := tx.QueryRow(`
+				INSERT INTO packages (run_id, name, path, import_path, files) VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (run_id, path, name) DO UPDATE SET import_path = excluded.import_path, files = excluded.files
+				RETURNING id`,
+				runID, r.Name, r.Path, r.ImportPath, r.Files).Scan(&pkgID)
+			if err != nil {
+				return fmt.Errorf("inserting package %s: %w", r.Path, err)
+			}
+			for metric, v := range r.Metrics {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return fmt.Errorf("encoding %s of %s: %w", metric, r.Path, err)
+				}
+				_, err = tx.Exec(`
+					INSERT INTO metrics (package_id, name, value) VALUES (?, ?, ?)
+					ON CONFLICT (package_id, name) DO UPDATE SET value = excluded.value`,
+					pkgID, metric, string(b))
+				if err != nil {
+					return fmt.Errorf("inserting %s of %s: %w", metric, r.Path, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// violations lists what --strict fails on across every package analysed:
+// findings of warning severity, as SARIF reports them, such as those of the
+// checks and overly complex functions, files longer than --outlier-factor
+// times the mean, functions longer than --max-func-lines, undocumented
+// exported symbols and a poor maintainability index.
+func violations(targets []target) []string {
+	out := []string{}
+	for _, t := range targets {
+		displayRoot = t.root
+		for _, r := range t.results {
+			for _, f := range r.Findings {
+				if f.Severity != "warning" {
+					continue
+				}
+				pos := token.Position{Filename: displayPath(f.File), Line: f.Line, Column: f.Column}
+				out = append(out, fmt.Sprintf("%s: %s: %s (%s)", r.Path, pos, f.Message, f.Rule))
+			}
+
+			lines, _ := r.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+			for _, o := range lines.Outliers(outlierFactor) {
+				out = append(out, fmt.Sprintf("%s: %s has %d line(s), over %gx the mean", r.Path, displayPath(o.File), o.Lines, outlierFactor))
+			}
+
+			if maxFuncLines > 0 {
+				funcs, _ := r.Metrics[analyser.FuncLinesMetric].([]analyser.FuncLength)
+				for _, f := range analyser.LongFuncs(funcs, maxFuncLines) {
+					f.Pos.Filename = displayPath(f.Pos.Filename)
+					out = append(out, fmt.Sprintf("%s: %s: %s spans %d lines, over %d", r.Path, f.Pos, f.Func, f.Lines, maxFuncLines))
+				}
+			}
+
+			docs, _ := r.Metrics[analyser.DocsMetric].(analyser.DocCoverage)
+			for _, s := range docs.Undocumented {
+				s.Pos.Filename = displayPath(s.Pos.Filename)
+				out = append(out, fmt.Sprintf("%s: %s: %s %s has no doc comment", r.Path, s.Pos, s.Kind, s.Name))
+			}
+
+			mi, _ := r.Metrics[analyser.MaintainabilityMetric].(analyser.Maintainability)
+			if mi.Label == "poor" {
+				out = append(out, fmt.Sprintf("%s: maintainability index is %.1f/100 (%s)", r.Path, mi.Index, mi.Label))
+			}
+		}
+	}
+	return out
+}
+
+func printViolations(w io.Writer, violations []string) {
+	fmt.Fprintf(w, "Strict mode found %d problem(s):\n", len(violations))
+	for _, v := range violations {
+		fmt.Fprintf(w, "  %s\n", v)
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// savedResult is the part of a result written by --output-dir that symbols
+// are compared with. Metrics are decoded on demand, as their types are only
+// known by name.
+type savedResult struct {
+	Name    string                     `json:"name"`
+	Metrics map[string]json.RawMessage `json:"metrics"`
+}
+
+// savedSymbols is the name and exported API of a package saved by
+// --output-dir.
+type savedSymbols struct {
+	name    string
+	symbols []analyser.APISymbol
+}
+
+// baselineSymbols is loaded from --baseline-file, once for every package
+// compared with it.
+var baselineSymbols *savedSymbols
+
+// loadSavedSymbols reads the name and exported API of the package whose
+// result was saved as JSON in name.
+func loadSavedSymbols(name string) (*savedSymbols, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline file: %w", err)
+	}
+	saved := savedResult{}
+	if err = json.Unmarshal(b, &saved); err != nil {
+		return nil, fmt.Errorf("decoding baseline file: %w", err)
+	}
+	raw, ok := saved.Metrics[analyser.APIMetric]
+	if !ok {
+		return nil, fmt.Errorf("baseline file %s has no %s metric", name, analyser.APIMetric)
+	}
+	symbols := []analyser.APISymbol{}
+	if err = json.Unmarshal(raw, &symbols); err != nil {
+		return nil, fmt.Errorf("decoding %s metric of baseline file: %w", analyser.APIMetric, err)
+	}
+	return &savedSymbols{name: saved.Name, symbols: symbols}, nil
+}
+
+// printSymbolChanges lists the exported symbols added to and removed from
+// the package since the result saved in --baseline-file, noting instead when
+// that is of another package.
+func printSymbolChanges(w io.Writer, r analyser.Result, saved *savedSymbols) {
+	if saved.name != r.Name {
+		fmt.Fprintf(w, "No symbols compared: %s holds package %q, not %q\n", baselineFile, saved.name, r.Name)
+		return
+	}
+	after, _ := r.Metrics[analyser.APIMetric].([]analyser.APISymbol)
+
+	added, removed := symbolChanges(saved.symbols, after)
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Fprintf(w, "No exported symbols added or removed since %s\n", baselineFile)
+		return
+	}
+	fmt.Fprintf(w, "Compared with %s:\n", baselineFile)
+	if len(added) > 0 {
+		fmt.Fprintf(w, "  Added: %s\n", strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(w, "  Removed: %s\n", strings.Join(removed, ", "))
+	}
+}
+
+// symbolChanges returns the kind and name of the symbols only in after and
+// of those only in before, each sorted. Symbols whose signature changed are
+// in both and so are in neither.
+func symbolChanges(before, after []analyser.APISymbol) (added, removed []string) {
+	key := func(s analyser.APISymbol) string { return s.Kind + " " + s.Name }
+	had, has := map[string]bool{}, map[string]bool{}
+	for _, s := range before {
+		had[key(s)] = true
+	}
+	for _, s := range after {
+		has[key(s)] = true
+	}
+
+	added, removed = []string{}, []string{}
+	for _, k := range sortedKeys(has) {
+		if !had[k] {
+			added = append(added, k)
+		}
+	}
+	for _, k := range sortedKeys(had) {
+		if !has[k] {
+			removed = append(removed, k)
+		}
+	}
+	return added, removed
+}
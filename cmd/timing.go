@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Phases of an analysis timed for --timing.
+const (
+	phaseFetch   = "fetch"
+	phaseParse   = "parse"
+	phaseAnalyse = "analyse"
+)
+
+// phaseTimes accumulates the time spent in each phase across every package,
+// including those analysed concurrently.
+var phaseTimes = &timings{spent: map[string]time.Duration{}}
+
+type timings struct {
+	mu    sync.Mutex
+	spent map[string]time.Duration
+}
+
+// startPhase starts timing a phase, returning a func that stops it.
+func startPhase(phase string) func() {
+	start := time.Now()
+	return func() {
+		d := time.Since(start)
+		phaseTimes.mu.Lock()
+		defer phaseTimes.mu.Unlock()
+		phaseTimes.spent[phase] += d
+	}
+}
+
+// printTimings writes the time spent in each phase, along with the wall
+// time of the whole run. Phases of packages analysed concurrently overlap,
+// so they can add up to more than the wall time.
+func printTimings(w io.Writer, wall time.Duration) {
+	phaseTimes.mu.Lock()
+	defer phaseTimes.mu.Unlock()
+
+	fmt.Fprintln(w, "Timing:")
+	for _, phase := range []string{phaseFetch, phaseParse, phaseAnalyse} {
+		if d, ok := phaseTimes.spent[phase]; ok {
+			fmt.Fprintf(w, "  %-8s %s\n", phase, d.Round(time.Microsecond))
+		}
+	}
+	fmt.Fprintf(w, "  %-8s %s\n", "total", wall.Round(time.Microsecond))
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/trelore/package-analyser/analyser"
+)
+
+// tuiEntry is a package shown by the TUI, along with the root its file
+// paths are shown relative to.
+type tuiEntry struct {
+	root   string
+	result analyser.Result
+}
+
+// tuiScreen is one level of the TUI: a list of lines, optionally with a
+// cursor to choose between them.
+type tuiScreen struct {
+	title string
+	lines []string
+	// selectable lists have a cursor, others only scroll.
+	selectable bool
+	cursor     int
+	offset     int
+	// open builds the screen shown when the line under the cursor is chosen.
+	open func(i int) (tuiScreen, bool)
+}
+
+// tuiModel is a stack of screens, drilling from packages to a package's
+// files and metrics, and from a file to the functions declared in it.
+type tuiModel struct {
+	screens []tuiScreen
+	height  int
+}
+
+// runTUI lets the results of targets be browsed interactively.
+func runTUI(targets []target) error {
+	entries := []tuiEntry{}
+	for _, t := range targets {
+		for _, r := range t.results {
+			entries = append(entries, tuiEntry{root: t.root, result: r})
+		}
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no packages to show")
+	}
+
+	m := tuiModel{screens: []tuiScreen{packagesScreen(entries)}, height: 24}
+	return tea.NewProgram(m, tea.WithAltScreen()).Start()
+}
+
+func packagesScreen(entries []tuiEntry) tuiScreen {
+	s := tuiScreen{title: "Packages", selectable: true}
+	for _, e := range entries {
+		s.lines = append(s.lines, fmt.Sprintf("%s (%s)", e.result.Name, e.result.Path))
+	}
+	s.open = func(i int) (tuiScreen, bool) { return packageScreen(entries[i]), true }
+	return s
+}
+
+// packageScreen lists the metrics of a package followed by its files.
+func packageScreen(e tuiEntry) tuiScreen {
+	displayRoot = e.root
+	lines, _ := e.result.Metrics[analyser.FileLinesMetric].(analyser.LineStats)
+
+	s := tuiScreen{title: fmt.Sprintf("Package '%s'", e.result.Name), selectable: true}
+	s.lines = append(s.lines, "Metrics")
+	for _, fl := range lines.Files {
+		s.lines = append(s.lines, fmt.Sprintf("%s (%d line(s))", displayPath(fl.File), fl.Lines))
+	}
+	s.open = func(i int) (tuiScreen, bool) {
+		displayRoot = e.root
+		if i == 0 {
+			return metricsScreen(e.result), true
+		}
+		return fileScreen(e.result, lines.Files[i-1].File), true
+	}
+	return s
+}
+
+func metricsScreen(r analyser.Result) tuiScreen {
+	var b bytes.Buffer
+	if err := printResult(&b, r); err != nil {
+		fmt.Fprintf(&b, "Error: %v\n", err)
+	}
+	return tuiScreen{title: "Metrics", lines: strings.Split(strings.TrimRight(b.String(), "\n"), "\n")}
+}
+
+// fileScreen lists the functions declared in a file of a package.
+func fileScreen(r analyser.Result, file string) tuiScreen {
+	funcs, _ := r.Metrics[analyser.ComplexityMetric].([]analyser.FuncComplexity)
+
+	s := tuiScreen{title: displayPath(file)}
+	for _, f := range funcs {
+		if f.Pos.Filename == file {
+			s.lines = append(s.lines, fmt.Sprintf("%-40s line %-5d complexity %d", f.Func, f.Pos.Line, f.Complexity))
+		}
+	}
+	if len(s.lines) == 0 {
+		s.lines = []string{"No functions declared"}
+	}
+	return s
+}
+
+func (m tuiModel) Init() tea.Cmd { return nil }
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tea.KeyMsg:
+		s := &m.screens[len(m.screens)-1]
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			s.move(-1, m.visible())
+		case "down", "j":
+			s.move(1, m.visible())
+		case "enter", "right", "l":
+			if s.selectable && s.open != nil {
+				if next, ok := s.open(s.cursor); ok {
+					m.screens = append(m.screens, next)
+				}
+			}
+		case "esc", "backspace", "left", "h":
+			if len(m.screens) > 1 {
+				m.screens = m.screens[:len(m.screens)-1]
+			}
+		}
+	}
+	return m, nil
+}
+
+// visible is the number of lines of a screen that fit in the terminal,
+// leaving room for the title and help.
+func (m tuiModel) visible() int {
+	if m.height < 5 {
+		return 1
+	}
+	return m.height - 4
+}
+
+// move moves the cursor of a list, or scrolls a screen without one, keeping
+// it within the visible lines.
+func (s *tuiScreen) move(delta, visible int) {
+	if !s.selectable {
+		s.offset += delta
+		if last := len(s.lines) - visible; s.offset > last {
+			s.offset = last
+		}
+		if s.offset < 0 {
+			s.offset = 0
+		}
+		return
+	}
+
+	s.cursor += delta
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+	if s.cursor >= len(s.lines) {
+		s.cursor = len(s.lines) - 1
+	}
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+visible {
+		s.offset = s.cursor - visible + 1
+	}
+}
+
+func (m tuiModel) View() string {
+	s := m.screens[len(m.screens)-1]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", s.title)
+	end := s.offset + m.visible()
+	if end > len(s.lines) {
+		end = len(s.lines)
+	}
+	for i := s.offset; i < end; i++ {
+		prefix := "  "
+		if s.selectable && i == s.cursor {
+			prefix = "> "
+		}
+		fmt.Fprintf(&b, "%s%s\n", prefix, s.lines[i])
+	}
+
+	help := "↑/↓ move • q quit"
+	if s.selectable {
+		help = "↑/↓ move • enter open • q quit"
+	}
+	if len(m.screens) > 1 {
+		help += " • esc back"
+	}
+	fmt.Fprintf(&b, "\n%s", help)
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/trelore/package-analyser/analyser"
+	"golang.org/x/tools/go/packages"
+)
+
+// typedPackage is the type information of a type checked package.
+type typedPackage struct {
+	pkg  *types.Package
+	info *types.Info
+}
+
+// typedTree is a worktree on disk whose packages are type checked, as set by
+// --typed. go/packages lists the files of each package, only those matching
+// the current build constraints unlike parsing the directory, along with
+// everything they import, all of which is then type checked from source.
+// That takes much longer than parsing alone.
+type typedTree struct {
+	fsTree
+	// packages holds the type information of the packages of every
+	// directory parsed, by directory and then package name.
+	packages map[string]map[string]*typedPackage
+	// checked caches dependencies by package ID, as every directory of a
+	// module imports much the same packages.
+	checked map[string]*types.Package
+}
+
+func newTypedTree(root string) *typedTree {
+	return &typedTree{
+		fsTree:   newWorktree(root),
+		packages: map[string]map[string]*typedPackage{},
+		checked:  map[string]*types.Package{},
+	}
+}
+
+func (t *typedTree) parseDir(fset *token.FileSet, dir string) (map[string]*ast.Package, []string, error) {
+	_, names, err := t.fsTree.parseDir(token.NewFileSet(), dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Types are left to typeCheck: this version of go/packages can't work
+	// out type sizes with recent toolchains.
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports | packages.NeedDeps,
+		Dir:   dir,
+		Tests: true,
+	}
+	loaded, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading %s: %w", dir, err)
+	}
+
+	pkgs := map[string]*ast.Package{}
+	t.packages[dir] = map[string]*typedPackage{}
+	for _, p := range loaded {
+		if strings.HasSuffix(p.PkgPath, ".test") {
+			// the generated test main
+			continue
+		}
+		// with tests, a package is loaded both with and without its
+		// internal test files, so keep whichever has the most
+		if have, ok := pkgs[p.Name]; ok && len(have.Files) >= len(p.CompiledGoFiles) {
+			continue
+		}
+
+		// name files as parsing the directory would, rather than by
+		// their absolute path, for positions to print the same
+		local := []string{}
+		for _, name := range p.CompiledGoFiles {
+			if abs, err := filepath.Abs(dir); err == nil && filepath.Dir(name) == abs {
+				name = filepath.Join(dir, filepath.Base(name))
+			}
+			local = append(local, name)
+		}
+		files, err := parseFiles(fset, local)
+		if err != nil {
+			return nil, nil, err
+		}
+		typed, err := t.typeCheck(p, fset, files)
+		if err != nil {
+			return nil, nil, err
+		}
+		byName := map[string]*ast.File{}
+		for _, f := range files {
+			byName[fset.File(f.Pos()).Name()] = f
+		}
+		pkgs[p.Name] = &ast.Package{Name: p.Name, Files: byName}
+		t.packages[dir][p.Name] = typed
+	}
+	return pkgs, names, nil
+}
+
+// typeCheck type checks the parsed files of p, failing on the first error.
+func (t *typedTree) typeCheck(p *packages.Package, fset *token.FileSet, files []*ast.File) (*typedPackage, error) {
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}
+	conf := t.config(p, fset)
+	pkg, err := conf.Check(p.PkgPath, fset, files, info)
+	if err != nil {
+		return nil, fmt.Errorf("type checking %s: %w", p.PkgPath, err)
+	}
+	return &typedPackage{pkg: pkg, info: info}, nil
+}
+
+// config returns the configuration p is type checked with, importing its
+// dependencies by type checking them first.
+func (t *typedTree) config(p *packages.Package, fset *token.FileSet) types.Config {
+	return types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if path == "unsafe" {
+				return types.Unsafe, nil
+			}
+			dep, ok := p.Imports[path]
+			if !ok {
+				return nil, fmt.Errorf("%s doesn't import %s", p.PkgPath, path)
+			}
+			return t.dependency(dep, fset)
+		}),
+		Sizes: types.SizesFor("gc", runtime.GOARCH),
+	}
+}
+
+// dependency type checks an imported package. Errors within it are ignored
+// as it's not being analysed; whatever could be checked is still of use.
+func (t *typedTree) dependency(p *packages.Package, fset *token.FileSet) (*types.Package, error) {
+	if pkg, ok := t.checked[p.ID]; ok {
+		return pkg, nil
+	}
+	files, err := parseFiles(fset, p.CompiledGoFiles)
+	if err != nil {
+		return nil, err
+	}
+	conf := t.config(p, fset)
+	conf.Error = func(error) {}
+	pkg, _ := conf.Check(p.PkgPath, fset, files, nil)
+	t.checked[p.ID] = pkg
+	return pkg, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// parseFiles parses every file in names.
+func parseFiles(fset *token.FileSet, names []string) ([]*ast.File, error) {
+	out := []*ast.File{}
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// analyseTyped analyses the files of a package, using its type information
+// if it was type checked.
+func analyseTyped(path string, fset *token.FileSet, files []*ast.File, p *typedPackage) analyser.Result {
+	if p == nil {
+		return analyse(path, fset, files)
+	}
+	defer startPhase(phaseAnalyse)()
+	r := analyser.AnalyseTyped(fset, files, p.pkg, p.info)
+	r.Path = path
+	return r
+}